@@ -0,0 +1,32 @@
+package eerror
+
+const E_AGGREGATE = "E_AGGREGATE"
+
+/*
+Aggregate gathers multiple errors as the causes of a single E_AGGREGATE enhanced error, ignoring nil errors.
+The aggregated error is related through Is to each of its causes.
+
+  err := eerror.Aggregate("{failures} files failed to load", loadErrors...)
+*/
+func Aggregate(message string, errs ...error) Eerror {
+	e := NewError(E_AGGREGATE, message)
+	e.WithCauses(errs...)
+	e.withInternalAttributes("failures", len(e.causes))
+	return e
+}
+
+// WithCauses attaches errors as causes of the error, converting them as enhanced errors and ignoring nil errors
+func (e *Eerror) WithCauses(errs ...error) {
+	causes := e.causes[:len(e.causes):len(e.causes)]
+	for _, err := range errs {
+		if err != nil {
+			causes = append(causes, From(err))
+		}
+	}
+	e.causes = causes
+}
+
+// Causes returns the errors the error was caused by, as attached by WithCauses or Aggregate
+func (e Eerror) Causes() []Eerror {
+	return append([]Eerror{}, e.causes...)
+}
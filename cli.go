@@ -0,0 +1,44 @@
+package eerror
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// DebugEnv is the environment variable opting command line tools in for internal error details, when set to a non-empty value
+var DebugEnv = "EERROR_DEBUG"
+
+/*
+Print writes any error for the given audience, as displayed by a command line tool.
+The public audience gets the identifier and public message of the error, the internal one gets the error as formatted by Error.
+
+  E_USER_NOT_FOUND: user 42 not found
+*/
+func Print(w io.Writer, err interface{}, audience Audience) {
+	eerr := From(err)
+
+	if audience == AudienceInternal {
+		fmt.Fprintln(w, eerr.Error())
+		return
+	}
+	fmt.Fprintf(w, "%s: %s\n", eerr.identifier, eerr.PublicMessage())
+}
+
+/*
+Exit prints any error to the standard error output, then exits with status 1.
+The error is printed for the public audience, unless the DebugEnv environment variable is set.
+
+  if err := run(); err != nil {
+     eerror.Exit(err)
+  }
+*/
+func Exit(err interface{}) {
+	audience := AudiencePublic
+	if os.Getenv(DebugEnv) != "" {
+		audience = AudienceInternal
+	}
+
+	Print(os.Stderr, err, audience)
+	os.Exit(1)
+}
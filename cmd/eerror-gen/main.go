@@ -0,0 +1,223 @@
+/*
+Command eerror-gen generates identifier constants, templates and typed constructors from an error catalog file.
+Intended to be used with go generate:
+
+	//go:generate go run github.com/bLuka/EnhancedError/cmd/eerror-gen -in errors.json
+
+The catalog is a JSON file listing errors, with their identifier, message template, and required attributes (see internal/catalog):
+
+	{
+	   "errors": [
+	      {
+	         "id": "E_USER_NOT_FOUND",
+	         "message": "user {user_id} not found",
+	         "attributes": [{"name": "user_id", "type": "int64"}]
+	      }
+	   ]
+	}
+
+Each error produces an identifier constant (E_USER_NOT_FOUND), a template registered into the default registry (ErrUserNotFound),
+and a constructor taking the required attributes as parameters (NewUserNotFound(userID int64, attributeKeyValPairs ...interface{})).
+Attribute types declared by other packages are qualified by their import path, as time.Duration or github.com/google/uuid.UUID.
+Errors naming their own template in the catalog, as migrated sentinel errors do, only produce their constant and constructor.
+*/
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bLuka/EnhancedError/internal/catalog"
+)
+
+const eerrorImportPath = "github.com/bLuka/EnhancedError"
+
+func main() {
+	in := flag.String("in", "errors.json", "error catalog file")
+	out := flag.String("out", "", "generated file (defaults to the catalog file name, suffixed by _gen.go)")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "generated file package name")
+	flag.Parse()
+
+	if *out == "" {
+		*out = strings.TrimSuffix(*in, filepath.Ext(*in)) + "_gen.go"
+	}
+	if *pkg == "" {
+		fail(fmt.Errorf("missing package name, use -package outside of go generate"))
+	}
+
+	errors, err := catalog.Read(*in)
+	if err != nil {
+		fail(err)
+	}
+	source, err := Generate(errors, *pkg, filepath.Base(*in))
+	if err != nil {
+		fail(err)
+	}
+	if err := os.WriteFile(*out, source, 0644); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "eerror-gen:", err)
+	os.Exit(1)
+}
+
+// qualifiedIdentifier matches the identifiers of attribute types declared by other packages, qualified by their import path
+var qualifiedIdentifier = regexp.MustCompile(`((?:[\w.~-]+/)*[A-Za-z_]\w*)\.([A-Za-z_]\w*)`)
+
+/*
+Generate produces the formatted Go source declaring the catalog errors.
+Attribute types declared by other packages are qualified by their import path, imported by the generated source:
+time.Duration, or github.com/google/uuid.UUID.
+*/
+func Generate(errors catalog.Catalog, pkg, source string) ([]byte, error) {
+	var b bytes.Buffer
+
+	sources, imports, err := attributeTypes(errors)
+	if err != nil {
+		return nil, err
+	}
+	importPaths := make([]string, 0, len(imports))
+	for importPath := range imports {
+		importPaths = append(importPaths, importPath)
+	}
+	sort.Strings(importPaths)
+
+	fmt.Fprintf(&b, "// Code generated by eerror-gen from %s. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	b.WriteString("import (\n")
+	for _, importPath := range importPaths {
+		if name := imports[importPath]; name != path.Base(importPath) {
+			fmt.Fprintf(&b, "\t%s %q\n", name, importPath)
+		} else {
+			fmt.Fprintf(&b, "\t%q\n", importPath)
+		}
+	}
+	b.WriteString(")\n\n")
+
+	b.WriteString("const (\n")
+	for _, e := range errors.Errors {
+		fmt.Fprintf(&b, "\t%s = %q\n", catalog.ConstantName(e.ID), e.ID)
+	}
+	b.WriteString(")\n\n")
+
+	var templates bytes.Buffer
+	for _, e := range errors.Errors {
+		if e.Template != "" {
+			continue
+		}
+		fmt.Fprintf(&templates, "\t// Err%s: %s\n", e.TypeName(), e.Message)
+		fmt.Fprintf(&templates, "\tErr%s = eerror.Define(%s, %q)", e.TypeName(), catalog.ConstantName(e.ID), e.Message)
+		if len(e.Attributes) > 0 {
+			names := make([]string, len(e.Attributes))
+			for i, attribute := range e.Attributes {
+				names[i] = strconv.Quote(attribute.Name)
+			}
+			fmt.Fprintf(&templates, ".Requires(%s)", strings.Join(names, ", "))
+		}
+		templates.WriteString("\n")
+	}
+	if templates.Len() > 0 {
+		fmt.Fprintf(&b, "var (\n%s)\n", templates.String())
+	}
+
+	for _, e := range errors.Errors {
+		params := make([]string, 0, len(e.Attributes)+1)
+		pairs := make([]string, 0, len(e.Attributes))
+		for _, attribute := range e.Attributes {
+			params = append(params, parameterName(attribute.Name)+" "+sources[attribute.Type])
+			pairs = append(pairs, fmt.Sprintf("%q, %s", attribute.Name, parameterName(attribute.Name)))
+		}
+		params = append(params, "attributeKeyValPairs ...interface{}")
+
+		fmt.Fprintf(&b, "\n// New%s instanciates a new %s error: %s\n", e.TypeName(), e.ID, e.Message)
+		fmt.Fprintf(&b, "func New%s(%s) eerror.Eerror {\n", e.TypeName(), strings.Join(params, ", "))
+		if len(pairs) > 0 {
+			fmt.Fprintf(&b, "\treturn %s.Instance(append([]interface{}{%s}, attributeKeyValPairs...)...)\n", e.TemplateName(), strings.Join(pairs, ", "))
+		} else {
+			fmt.Fprintf(&b, "\treturn %s.Instance(attributeKeyValPairs...)\n", e.TemplateName())
+		}
+		b.WriteString("}\n")
+	}
+
+	return format.Source(b.Bytes())
+}
+
+// attributeTypes returns the Go source of the attribute types of the catalog, and the names of the packages they import by import path
+func attributeTypes(errors catalog.Catalog) (map[string]string, map[string]string, error) {
+	sources := map[string]string{"": "interface{}"}
+	imports := map[string]string{eerrorImportPath: "eerror"}
+	names := map[string]string{"eerror": eerrorImportPath}
+
+	for _, e := range errors.Errors {
+		for _, attribute := range e.Attributes {
+			if _, ok := sources[attribute.Type]; ok {
+				continue
+			}
+
+			var err error
+			source := qualifiedIdentifier.ReplaceAllStringFunc(attribute.Type, func(qualified string) string {
+				match := qualifiedIdentifier.FindStringSubmatch(qualified)
+				importPath, name := match[1], packageName(match[1])
+				if known, ok := names[name]; ok && known != importPath {
+					err = fmt.Errorf("attribute %s of %s: package %s conflicts with %s", attribute.Name, e.ID, importPath, known)
+				}
+				names[name], imports[importPath] = importPath, name
+				return name + "." + match[2]
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+			if _, err := parser.ParseExpr(source); err != nil {
+				return nil, nil, fmt.Errorf("attribute %s of %s: invalid type %s", attribute.Name, e.ID, attribute.Type)
+			}
+			sources[attribute.Type] = source
+		}
+	}
+	return sources, imports, nil
+}
+
+// packageName names the import of a package after the last element of its import path, major versions aside
+func packageName(importPath string) string {
+	name := path.Base(importPath)
+	if major := strings.TrimPrefix(name, "v"); major != name && major != "" && strings.Trim(major, "0123456789") == "" && path.Dir(importPath) != "." {
+		name = path.Base(path.Dir(importPath))
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '~' {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// parameterName turns an attribute name into a parameter name, user_id being declared as userID
+func parameterName(attribute string) string {
+	var name strings.Builder
+	for i, word := range catalog.Words(attribute) {
+		if i == 0 {
+			name.WriteString(strings.ToLower(word))
+		} else {
+			name.WriteString(catalog.Capitalize(word))
+		}
+	}
+
+	parameter := name.String()
+	if parameter == "" || !token.IsIdentifier(parameter) || types.Universe.Lookup(parameter) != nil || parameter == "attributeKeyValPairs" {
+		parameter += "_"
+	}
+	return parameter
+}
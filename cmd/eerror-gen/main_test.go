@@ -0,0 +1,79 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/bLuka/EnhancedError/internal/catalog"
+)
+
+// TestGenerate ensures constants, templates and typed constructors are generated from a catalog
+func TestGenerate(t *testing.T) {
+	errors := catalog.Catalog{Errors: []catalog.Error{
+		{ID: "E_USER_NOT_FOUND", Message: "user {user_id} not found", Attributes: []catalog.Attribute{{Name: "user_id", Type: "int64"}}},
+		{ID: "E_DB.TIMEOUT", Message: "database timeout"},
+		{ID: "E_INVALID_TYPE", Name: "BadType", Message: "invalid {type}", Attributes: []catalog.Attribute{{Name: "type"}}},
+		{ID: "E_USER_LOCKED", Message: "user locked", Template: "ErrLocked"},
+		{ID: "E_SLOW", Message: "slow {append} after {delay}", Attributes: []catalog.Attribute{{Name: "append", Type: "bool"}, {Name: "delay", Type: "[]time.Duration"}, {Name: "url", Type: "*net/url.URL"}}},
+	}}
+
+	source, err := Generate(errors, "errs", "errors.json")
+	if err != nil {
+		t.Fatal("Generation should succeed\n", err)
+	}
+	fset := token.NewFileSet()
+	generated, err := parser.ParseFile(fset, "errors_gen.go", source, 0)
+	if err != nil {
+		t.Fatal("Generated source should be valid\n", err, "\n", string(source))
+	}
+	declared, err := parser.ParseFile(fset, "errors.go", `package errs
+
+import eerror "github.com/bLuka/EnhancedError"
+
+var ErrLocked = eerror.Define(E_USER_LOCKED, "user locked")
+`, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := config.Check("errs", fset, []*ast.File{generated, declared}, nil); err != nil {
+		t.Fatal("Generated source should type-check\n", err, "\n", string(source))
+	}
+
+	for _, expect := range []string{
+		"// Code generated by eerror-gen from errors.json. DO NOT EDIT.",
+		"package errs",
+		`E_USER_NOT_FOUND = "E_USER_NOT_FOUND"`,
+		`E_DB_TIMEOUT     = "E_DB.TIMEOUT"`,
+		`ErrUserNotFound = eerror.Define(E_USER_NOT_FOUND, "user {user_id} not found").Requires("user_id")`,
+		"func NewUserNotFound(userID int64, attributeKeyValPairs ...interface{}) eerror.Eerror {",
+		`return ErrUserNotFound.Instance(append([]interface{}{"user_id", userID}, attributeKeyValPairs...)...)`,
+		"func NewDbTimeout(attributeKeyValPairs ...interface{}) eerror.Eerror {",
+		"func NewBadType(type_ interface{}, attributeKeyValPairs ...interface{}) eerror.Eerror {",
+		"return ErrLocked.Instance(attributeKeyValPairs...)",
+		"func NewSlow(append_ bool, delay []time.Duration, url *url.URL, attributeKeyValPairs ...interface{}) eerror.Eerror {",
+		`"net/url"`,
+		`"time"`,
+	} {
+		if !strings.Contains(string(source), expect) {
+			t.Error("Generated source should contain (expected, source)\n", expect, "\n", string(source))
+		}
+	}
+	if strings.Contains(string(source), "ErrUserLocked") {
+		t.Error("Templates declared by hand shouldn't be generated\n", string(source))
+	}
+
+	for _, attribute := range []catalog.Attribute{{Name: "delay", Type: "map[string"}, {Name: "delay", Type: "example.com/time.Duration"}} {
+		invalid := catalog.Catalog{Errors: []catalog.Error{
+			{ID: "E_SLOW", Message: "slow", Attributes: []catalog.Attribute{{Name: "timeout", Type: "time.Duration"}, attribute}},
+		}}
+		if _, err := Generate(invalid, "errs", "errors.json"); err == nil {
+			t.Error("Generation should fail on invalid or conflicting attribute types\n", attribute.Type)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const diffContext = 3
+
+// diffLine is a line of an edit script: kept (' '), removed ('-') or added ('+')
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// unifiedDiff returns the unified diff of two versions of a file, or nothing if they are equal
+func unifiedDiff(path string, original, rewritten []byte) []byte {
+	edits := diffLines(splitLines(original), splitLines(rewritten))
+
+	var diff bytes.Buffer
+	writeHunk := func(start, end int) {
+		if diff.Len() == 0 {
+			fmt.Fprintf(&diff, "--- %s.orig\n+++ %s\n", path, path)
+		}
+
+		var originalLine, rewrittenLine, originalCount, rewrittenCount int
+		for i, edit := range edits[:end] {
+			original, rewritten := edit.op != '+', edit.op != '-'
+			switch {
+			case i < start && original:
+				originalLine++
+			case i >= start && original:
+				originalCount++
+			}
+			switch {
+			case i < start && rewritten:
+				rewrittenLine++
+			case i >= start && rewritten:
+				rewrittenCount++
+			}
+		}
+
+		fmt.Fprintf(&diff, "@@ -%s +%s @@\n", hunkRange(originalLine, originalCount), hunkRange(rewrittenLine, rewrittenCount))
+		for _, edit := range edits[start:end] {
+			diff.WriteByte(edit.op)
+			diff.WriteString(edit.text)
+			if !strings.HasSuffix(edit.text, "\n") {
+				diff.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+	}
+
+	// Changes close enough to share their context lines are grouped in a same hunk
+	start, end := -1, -1
+	for i, edit := range edits {
+		if edit.op == ' ' {
+			continue
+		}
+		if start != -1 && i-diffContext <= end {
+			end = min(len(edits), i+diffContext+1)
+			continue
+		}
+		if start != -1 {
+			writeHunk(start, end)
+		}
+		start, end = max(0, i-diffContext), min(len(edits), i+diffContext+1)
+	}
+	if start != -1 {
+		writeHunk(start, end)
+	}
+	return diff.Bytes()
+}
+
+func hunkRange(line, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", line)
+	case 1:
+		return fmt.Sprintf("%d", line+1)
+	}
+	return fmt.Sprintf("%d,%d", line+1, count)
+}
+
+func splitLines(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes the shortest edit script between two lists of lines, through the Myers algorithm
+func diffLines(a, b []string) []diffLine {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+
+	var trace [][]int
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int{}, v...))
+		done := false
+		for k := -d; k <= d && !done; k += 2 {
+			x := v[offset+k-1] + 1
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			done = x >= n && y >= m
+		}
+		if done {
+			break
+		}
+	}
+
+	var edits []diffLine
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		previous := k - 1
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			previous = k + 1
+		}
+		previousX := v[offset+previous]
+		previousY := previousX - previous
+
+		for x > previousX && y > previousY {
+			x, y = x-1, y-1
+			edits = append(edits, diffLine{' ', a[x]})
+		}
+		if d > 0 {
+			if x == previousX {
+				edits = append(edits, diffLine{'+', b[y-1]})
+			} else {
+				edits = append(edits, diffLine{'-', a[x-1]})
+			}
+		}
+		x, y = previousX, previousY
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
@@ -0,0 +1,165 @@
+/*
+Command eerror-migrate rewrites errors.New and fmt.Errorf calls to enhanced errors.
+
+  eerror-migrate [-w] [-catalog errors.json] ./...
+
+Calls are converted to eerror.NewError, or eerror.Wrap when wrapping an error through %w.
+Package-level sentinel errors are converted to eerror.Define templates, so that comparisons against them keep working.
+Their templates are named in the catalog, so that eerror-gen only generates their constants and constructors.
+Format verbs become attributes, named after the formatted expressions, and rendered in the message template:
+
+  fmt.Errorf("user %d not found", userID)
+  // becomes
+  eerror.NewError(E_USER_NOT_FOUND, "user {user_id} not found", "user_id", userID)
+
+Placeholder identifiers are added to the catalog file of each package, to be renamed at will then generated by eerror-gen.
+By default, the changes are printed as a diff for review; -w writes them.
+Calls that cannot be converted (non-literal formats, unsupported verbs, ...) are reported, and left untouched.
+*/
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bLuka/EnhancedError/internal/catalog"
+)
+
+func main() {
+	write := flag.Bool("w", false, "write changes to the files instead of printing a diff")
+	catalogName := flag.String("catalog", "errors.json", "catalog file name, in each package directory")
+	flag.Parse()
+
+	directories, err := packageDirectories(flag.Args())
+	if err != nil {
+		fail(err)
+	}
+
+	reported := false
+	for _, directory := range directories {
+		reports, err := migrate(directory, filepath.Join(directory, *catalogName), *write)
+		if err != nil {
+			fail(err)
+		}
+		for _, report := range reports {
+			fmt.Fprintln(os.Stderr, report)
+			reported = true
+		}
+	}
+	if reported {
+		os.Exit(1)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "eerror-migrate:", err)
+	os.Exit(2)
+}
+
+// migrate rewrites the Go files of a directory, returning the reports of unconvertible calls
+func migrate(directory, catalogPath string, write bool) ([]string, error) {
+	var errors catalog.Catalog
+	if _, err := os.Stat(catalogPath); err == nil {
+		if errors, err = catalog.Read(catalogPath); err != nil {
+			return nil, err
+		}
+	}
+
+	paths, err := filepath.Glob(filepath.Join(directory, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	fset := token.NewFileSet()
+	migration := NewMigration(fset, errors)
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_gen.go") {
+			continue
+		}
+
+		original, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		file, err := parser.ParseFile(fset, path, original, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if !migration.Rewrite(file) {
+			continue
+		}
+
+		var rewritten bytes.Buffer
+		if err := format.Node(&rewritten, fset, file); err != nil {
+			return nil, err
+		}
+		if err := output(path, original, rewritten.Bytes(), write); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(migration.Catalog.Errors) > len(errors.Errors) {
+		original, _ := os.ReadFile(catalogPath)
+		rewritten, err := json.MarshalIndent(migration.Catalog, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := output(catalogPath, original, append(rewritten, '\n'), write); err != nil {
+			return nil, err
+		}
+	}
+	return migration.Reports, nil
+}
+
+// output writes the rewritten file, or prints its diff
+func output(path string, original, rewritten []byte, write bool) error {
+	if write {
+		return os.WriteFile(path, rewritten, 0644)
+	}
+
+	_, err := os.Stdout.Write(unifiedDiff(path, original, rewritten))
+	return err
+}
+
+// packageDirectories resolves the directories to migrate, "dir/..." standing for dir and its subdirectories
+func packageDirectories(args []string) ([]string, error) {
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	var directories []string
+	for _, arg := range args {
+		if !strings.HasSuffix(arg, "/...") {
+			directories = append(directories, arg)
+			continue
+		}
+
+		err := filepath.Walk(strings.TrimSuffix(arg, "/..."), func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				return nil
+			}
+			if name := info.Name(); path != strings.TrimSuffix(arg, "/...") && (name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			directories = append(directories, path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return directories, nil
+}
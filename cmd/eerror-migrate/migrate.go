@@ -0,0 +1,410 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/ast/astutil"
+
+	"github.com/bLuka/EnhancedError/internal/catalog"
+)
+
+const eerrorImportPath = "github.com/bLuka/EnhancedError"
+
+// Migration rewrites errors.New and fmt.Errorf calls to enhanced errors, collecting their identifiers into a catalog
+type Migration struct {
+	Catalog catalog.Catalog
+	Reports []string
+
+	fset    *token.FileSet
+	entries map[string]int
+	// generated lists the catalog errors whose template is expected from eerror-gen, as already generated or referenced
+	generated map[int]bool
+}
+
+// NewMigration instanciates a migration, adding identifiers to the given catalog
+func NewMigration(fset *token.FileSet, errors catalog.Catalog) *Migration {
+	m := &Migration{
+		Catalog:   errors,
+		fset:      fset,
+		entries:   make(map[string]int, len(errors.Errors)),
+		generated: make(map[int]bool, len(errors.Errors)),
+	}
+	for i, e := range errors.Errors {
+		m.entries[e.Message] = i
+		m.generated[i] = e.Template == ""
+	}
+	return m
+}
+
+// Rewrite converts the calls of a file, returning whether it changed
+func (m *Migration) Rewrite(file *ast.File) bool {
+	fmtName, errorsName := importName(file, "fmt"), importName(file, "errors")
+	if fmtName == "" && errorsName == "" {
+		return false
+	}
+	eerrorName := importName(file, eerrorImportPath)
+	if eerrorName == "" {
+		eerrorName = "eerror"
+	}
+
+	sentinels := packageLevelCalls(file)
+	changed := false
+	astutil.Apply(file, nil, func(c *astutil.Cursor) bool {
+		call, ok := c.Node().(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pkg, ok := selector.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+
+		var replacement ast.Expr
+		var err error
+		variable, sentinel := sentinels[call]
+		switch {
+		case pkg.Name == errorsName && selector.Sel.Name == "New" && sentinel:
+			replacement, err = m.rewriteSentinel(call, variable, eerrorName, false)
+		case pkg.Name == fmtName && selector.Sel.Name == "Errorf" && sentinel:
+			replacement, err = m.rewriteSentinel(call, variable, eerrorName, true)
+		case pkg.Name == errorsName && selector.Sel.Name == "New":
+			replacement, err = m.rewriteNew(call, eerrorName)
+		case pkg.Name == fmtName && selector.Sel.Name == "Errorf":
+			replacement, err = m.rewriteErrorf(call, eerrorName)
+		default:
+			return true
+		}
+
+		if err != nil {
+			m.Reports = append(m.Reports, fmt.Sprintf("%s: cannot convert %s.%s: %v", m.fset.Position(call.Pos()), pkg.Name, selector.Sel.Name, err))
+			return true
+		}
+		c.Replace(replacement)
+		changed = true
+		return true
+	})
+	if !changed {
+		return false
+	}
+
+	if importName(file, eerrorImportPath) == "" {
+		astutil.AddNamedImport(m.fset, file, "eerror", eerrorImportPath)
+	}
+	for _, path := range []string{"fmt", "errors"} {
+		if !astutil.UsesImport(file, path) {
+			astutil.DeleteImport(m.fset, file, path)
+		}
+	}
+	return true
+}
+
+func (m *Migration) rewriteNew(call *ast.CallExpr, eerrorName string) (ast.Expr, error) {
+	if len(call.Args) != 1 {
+		return nil, fmt.Errorf("unexpected arguments")
+	}
+	message, err := stringLiteral(call.Args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return callExpr(eerrorName, "NewError", ast.NewIdent(m.identifier(message, nil)), quote(message)), nil
+}
+
+/*
+rewriteSentinel converts a package-level sentinel error to a template, as an enhanced error is neither comparable nor matched by errors.Is.
+Templates are pointers: comparisons against the sentinel keep working, and instances relate to it through errors.Is.
+The template is declared by the sentinel variable, named in the catalog so that eerror-gen doesn't declare it again.
+Sentinels whose template is already declared, by another sentinel or eerror-gen, refer to it instead.
+*/
+func (m *Migration) rewriteSentinel(call *ast.CallExpr, variable, eerrorName string, errorf bool) (ast.Expr, error) {
+	if len(call.Args) != 1 {
+		return nil, fmt.Errorf("sentinel with arguments")
+	}
+	message, err := stringLiteral(call.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	if errorf {
+		verbs, err := parseVerbs(message)
+		if err != nil {
+			return nil, err
+		}
+		if len(verbs) > 0 {
+			return nil, fmt.Errorf("sentinel with arguments")
+		}
+		message = unescapePercents(message)
+	}
+
+	index := m.entry(message, nil)
+	e := &m.Catalog.Errors[index]
+	if e.Template != "" || variable == "" || m.generated[index] {
+		m.generated[index] = e.Template == ""
+		return ast.NewIdent(e.TemplateName()), nil
+	}
+	e.Template = variable
+	return callExpr(eerrorName, "Define", ast.NewIdent(catalog.ConstantName(e.ID)), quote(message)), nil
+}
+
+func (m *Migration) rewriteErrorf(call *ast.CallExpr, eerrorName string) (ast.Expr, error) {
+	if len(call.Args) < 1 || call.Ellipsis.IsValid() {
+		return nil, fmt.Errorf("unexpected arguments")
+	}
+	format, err := stringLiteral(call.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	verbs, err := parseVerbs(format)
+	if err != nil {
+		return nil, err
+	}
+	if len(verbs) != len(call.Args)-1 {
+		return nil, fmt.Errorf("%d verbs for %d arguments", len(verbs), len(call.Args)-1)
+	}
+
+	var wrapped ast.Expr
+	for i, verb := range verbs {
+		if verb.verb != 'w' {
+			continue
+		}
+		if wrapped != nil || i != len(verbs)-1 || verb.end != len(format) {
+			return nil, fmt.Errorf("%%w should be the last verb, ending the format")
+		}
+		wrapped = call.Args[i+1]
+		format = strings.TrimRight(format[:verb.start], ": ")
+		verbs = verbs[:i]
+	}
+
+	var template strings.Builder
+	var attributes []string
+	var pairs []ast.Expr
+	names := make(map[string]bool, len(verbs))
+	last := 0
+	for i, verb := range verbs {
+		name := uniqueName(attributeName(call.Args[i+1], i), names)
+		template.WriteString(unescapePercents(format[last:verb.start]))
+		template.WriteString("{" + name + "}")
+		last = verb.end
+
+		attributes = append(attributes, name)
+		pairs = append(pairs, quote(name), call.Args[i+1])
+	}
+	template.WriteString(unescapePercents(format[last:]))
+
+	if wrapped != nil {
+		return callExpr(eerrorName, "Wrap", append([]ast.Expr{wrapped, quote(template.String())}, pairs...)...), nil
+	}
+	return callExpr(eerrorName, "NewError", append([]ast.Expr{ast.NewIdent(m.identifier(template.String(), attributes)), quote(template.String())}, pairs...)...), nil
+}
+
+// identifier returns the constant name of the identifier of a message, adding it to the catalog on first use
+func (m *Migration) identifier(message string, attributes []string) string {
+	return catalog.ConstantName(m.Catalog.Errors[m.entry(message, attributes)].ID)
+}
+
+// entry returns the index of the catalog error of a message, adding it on first use
+func (m *Migration) entry(message string, attributes []string) int {
+	if index, ok := m.entries[message]; ok {
+		return index
+	}
+
+	var words []string
+	for _, word := range strings.FieldsFunc(removePlaceholders(message), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(words) == 4 {
+			break
+		}
+		words = append(words, strings.ToUpper(word))
+	}
+	base := "E_" + strings.Join(words, "_")
+	if len(words) == 0 {
+		base = "E_ERROR"
+	}
+
+	identifier := base
+	taken := m.Catalog.Identifiers()
+	for i := 2; taken[identifier]; i++ {
+		identifier = base + "_" + strconv.Itoa(i)
+	}
+
+	e := catalog.Error{ID: identifier, Message: message}
+	for _, attribute := range attributes {
+		e.Attributes = append(e.Attributes, catalog.Attribute{Name: attribute})
+	}
+	m.Catalog.Errors = append(m.Catalog.Errors, e)
+	m.entries[message] = len(m.Catalog.Errors) - 1
+	return m.entries[message]
+}
+
+// packageLevelCalls lists the calls evaluated by package-level variable declarations, outside of function literals,
+// along with the name of the variable they are directly assigned to, if any
+func packageLevelCalls(file *ast.File) map[*ast.CallExpr]string {
+	calls := make(map[*ast.CallExpr]string)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		ast.Inspect(gen, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.CallExpr:
+				if _, ok := calls[n]; !ok {
+					calls[n] = ""
+				}
+			case *ast.ValueSpec:
+				for i, value := range n.Values {
+					if call, ok := ast.Unparen(value).(*ast.CallExpr); ok && len(n.Names) == len(n.Values) && n.Names[i].Name != "_" {
+						calls[call] = n.Names[i].Name
+					}
+				}
+			}
+			return true
+		})
+	}
+	return calls
+}
+
+type verb struct {
+	start, end int
+	verb       byte
+}
+
+// parseVerbs lists the verbs of a format, rejecting the ones whose formatting would be lost as an attribute
+func parseVerbs(format string) ([]verb, error) {
+	var verbs []verb
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		if i+1 < len(format) && format[i+1] == '%' {
+			i++
+			continue
+		}
+		if i+1 >= len(format) {
+			return nil, fmt.Errorf("truncated verb")
+		}
+
+		switch format[i+1] {
+		case 'v', 's', 'd', 't', 'w':
+			verbs = append(verbs, verb{i, i + 2, format[i+1]})
+			i++
+		default:
+			return nil, fmt.Errorf("unsupported verb %q", format[i:i+2])
+		}
+	}
+	return verbs, nil
+}
+
+// attributeName infers an attribute name from the formatted expression: userID and u.UserID being named user_id
+func attributeName(expr ast.Expr, index int) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return snakeCase(expr.Name)
+	case *ast.SelectorExpr:
+		return snakeCase(expr.Sel.Name)
+	case *ast.CallExpr:
+		if len(expr.Args) == 0 {
+			return attributeName(expr.Fun, index)
+		}
+	case *ast.StarExpr:
+		return attributeName(expr.X, index)
+	}
+	return "arg" + strconv.Itoa(index+1)
+}
+
+func snakeCase(name string) string {
+	var snake strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			snake.WriteByte('_')
+		}
+		snake.WriteRune(unicode.ToLower(r))
+	}
+	return snake.String()
+}
+
+func uniqueName(name string, names map[string]bool) string {
+	unique := name
+	for i := 2; names[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	names[unique] = true
+	return unique
+}
+
+func removePlaceholders(message string) string {
+	var removed strings.Builder
+	inside := false
+	for _, r := range message {
+		switch {
+		case r == '{':
+			inside = true
+		case r == '}':
+			inside = false
+		case !inside:
+			removed.WriteRune(r)
+		}
+	}
+	return removed.String()
+}
+
+func unescapePercents(s string) string {
+	return strings.Replace(s, "%%", "%", -1)
+}
+
+func importName(file *ast.File, path string) string {
+	for _, spec := range file.Imports {
+		if importPath, _ := strconv.Unquote(spec.Path.Value); importPath != path {
+			continue
+		}
+		if spec.Name != nil {
+			return spec.Name.Name
+		}
+		if path == eerrorImportPath {
+			return "eerror"
+		}
+		return path
+	}
+	return ""
+}
+
+func stringLiteral(expr ast.Expr) (string, error) {
+	literal, ok := expr.(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return "", fmt.Errorf("message %s is not a string literal", nodeString(expr))
+	}
+	return strconv.Unquote(literal.Value)
+}
+
+func nodeString(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.SelectorExpr:
+		return nodeString(expr.X) + "." + expr.Sel.Name
+	}
+	return "expression"
+}
+
+func callExpr(pkg, name string, args ...ast.Expr) *ast.CallExpr {
+	return &ast.CallExpr{
+		Fun:  &ast.SelectorExpr{X: ast.NewIdent(pkg), Sel: ast.NewIdent(name)},
+		Args: args,
+	}
+}
+
+func quote(s string) *ast.BasicLit {
+	return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(s)}
+}
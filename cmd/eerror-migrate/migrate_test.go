@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bLuka/EnhancedError/internal/catalog"
+)
+
+const migrationSource = `package users
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotFound  = errors.New("user not found")
+	ErrLocked    = fmt.Errorf("user locked at 100%%")
+	ErrUnrelated = errors.New("unrelated")
+)
+
+func find(userID int) error {
+	if userID == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func missing(userID int) bool {
+	err := find(userID)
+	return err == ErrNotFound || errors.Is(err, ErrNotFound)
+}
+
+func load(userID int, path string, err error) error {
+	if err != nil {
+		return fmt.Errorf("loading %s: %w", path, err)
+	}
+	if userID < 0 {
+		return fmt.Errorf("invalid user %d at 100%%", userID)
+	}
+	fmt.Println("unrelated")
+	return fmt.Errorf("user %x", userID)
+}
+`
+
+const migrationExpected = `package users
+
+import (
+	"errors"
+	"fmt"
+	eerror "github.com/bLuka/EnhancedError"
+)
+
+var (
+	ErrNotFound  = eerror.Define(E_USER_NOT_FOUND, "user not found")
+	ErrLocked    = eerror.Define(E_USER_LOCKED_AT_100, "user locked at 100%")
+	ErrUnrelated = ErrUserNotFoundOld
+)
+
+func find(userID int) error {
+	if userID == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func missing(userID int) bool {
+	err := find(userID)
+	return err == ErrNotFound || errors.Is(err, ErrNotFound)
+}
+
+func load(userID int, path string, err error) error {
+	if err != nil {
+		return eerror.Wrap(err, "loading {path}", "path", path)
+	}
+	if userID < 0 {
+		return eerror.NewError(E_INVALID_USER_AT_100, "invalid user {user_id} at 100%", "user_id", userID)
+	}
+	fmt.Println("unrelated")
+	return fmt.Errorf("user %x", userID)
+}
+`
+
+// TestMigration ensures standard errors are rewritten to enhanced errors, and unconvertible calls reported
+func TestMigration(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "users.go", migrationSource, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migration := NewMigration(fset, catalog.Catalog{Errors: []catalog.Error{{ID: "E_USER_NOT_FOUND_OLD", Message: "unrelated"}}})
+	if !migration.Rewrite(file) {
+		t.Fatal("Migration should rewrite the file")
+	}
+
+	var rewritten bytes.Buffer
+	if err := format.Node(&rewritten, fset, file); err != nil {
+		t.Fatal(err)
+	}
+	if rewritten.String() != migrationExpected {
+		t.Error("Invalid rewritten file (result, expected)\n", rewritten.String(), "\n", migrationExpected)
+	}
+
+	if len(migration.Catalog.Errors) != 4 || migration.Catalog.Errors[3].ID != "E_INVALID_USER_AT_100" || migration.Catalog.Errors[3].Attributes[0].Name != "user_id" {
+		t.Error("Migration should add placeholder identifiers to the catalog\n", migration.Catalog)
+	}
+	if migration.Catalog.Errors[0].Template != "" || migration.Catalog.Errors[1].Template != "ErrNotFound" || migration.Catalog.Errors[2].Template != "ErrLocked" || migration.Catalog.Errors[3].Template != "" {
+		t.Error("Migration should name the templates declared by sentinel errors in the catalog\n", migration.Catalog)
+	}
+	if len(migration.Reports) != 1 || !strings.Contains(migration.Reports[0], `users.go:34:9: cannot convert fmt.Errorf: unsupported verb "%x"`) {
+		t.Error("Migration should report unconvertible calls\n", migration.Reports)
+	}
+}
+
+const generationSource = `package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotFound = errors.New("user not found")
+	ErrMissing  = errors.New("user not found")
+)
+
+func main() {
+	err := fmt.Errorf("loading: %w", ErrNotFound)
+	fmt.Println(errors.Is(err, ErrNotFound), ErrMissing == ErrNotFound, errors.Is(NewUserNotFound(), ErrNotFound))
+}
+`
+
+// TestMigrationGeneration ensures migrated packages build and run once their catalog is generated
+func TestMigrationGeneration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a module")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	directory := t.TempDir()
+	files := map[string]string{
+		"go.mod":  "module example.com/users\n\ngo 1.23\n\nrequire github.com/bLuka/EnhancedError v0.0.0\n\nreplace github.com/bLuka/EnhancedError => " + root + "\n",
+		"go.sum":  string(sum),
+		"main.go": generationSource,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(directory, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	catalogPath := filepath.Join(directory, "errors.json")
+	if reports, err := migrate(directory, catalogPath, true); err != nil || len(reports) != 0 {
+		t.Fatal("Migration failed\n", err, reports)
+	}
+
+	run := func(directory string, args ...string) string {
+		command := exec.Command("go", args...)
+		command.Dir = directory
+		command.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off")
+		output, err := command.CombinedOutput()
+		if err != nil {
+			t.Fatal("Command go ", strings.Join(args, " "), " failed\n", err, "\n", string(output))
+		}
+		return string(output)
+	}
+	run(filepath.Join(root, "cmd", "eerror-gen"), "run", ".", "-in", catalogPath, "-package", "main")
+	if output := run(directory, "run", "."); output != "true true true\n" {
+		t.Error("Invalid migrated program output\n", output)
+	}
+}
+
+// TestUnifiedDiff ensures diffs are unified, grouping close changes in a same hunk
+func TestUnifiedDiff(t *testing.T) {
+	original := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\no\np"
+	rewritten := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\no\np\nq\n"
+	expected := `--- file.go.orig
++++ file.go
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -13,4 +13,5 @@
+ m
+ n
+ o
+-p
+\ No newline at end of file
++p
++q
+`
+	if diff := string(unifiedDiff("file.go", []byte(original), []byte(rewritten))); diff != expected {
+		t.Error("Invalid unified diff (result, expected)\n", diff, "\n", expected)
+	}
+	if diff := unifiedDiff("file.go", []byte(original), []byte(original)); len(diff) != 0 {
+		t.Error("Equal files shouldn't differ\n", string(diff))
+	}
+}
@@ -0,0 +1,18 @@
+/*
+Command eerror-vet checks enhanced errors call sites, as described by the eerrorcheck package.
+Runnable standalone, or as a vet tool:
+
+  eerror-vet -catalog errors.json ./...
+  go vet -vettool=$(which eerror-vet) ./...
+*/
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/bLuka/EnhancedError/eerrorcheck"
+)
+
+func main() {
+	singlechecker.Main(eerrorcheck.Analyzer)
+}
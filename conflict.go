@@ -0,0 +1,181 @@
+package eerror
+
+const E_ATTRIBUTECONFLICT = "E_ATTRIBUTECONFLICT"
+
+// AttributeOrder describes the order attributes are formatted in
+type AttributeOrder int
+
+const (
+	// OrderSorted formats attributes sorted by key (default)
+	OrderSorted AttributeOrder = iota
+	// OrderInsertion formats attributes in the order they were first set
+	OrderInsertion
+)
+
+// ConflictPolicy describes how an attribute already set on an error is handled when set again
+type ConflictPolicy int
+
+const (
+	// ConflictOverwrite replaces the previous value (default)
+	ConflictOverwrite ConflictPolicy = iota
+	// ConflictKeepFirst keeps the previous value, ignoring the new one
+	ConflictKeepFirst
+	// ConflictError keeps the previous value, recording an E_ATTRIBUTECONFLICT error as a cause of the error. Meant to catch conflicts in development and tests
+	ConflictError
+	// ConflictHistory replaces the previous value, but keeps it in the attribute history along with the context it was set in.
+	// The history is exposed by AttributeHistory and Map, not formatted by Error
+	ConflictHistory
+)
+
+// AttributeRecord describes a value an attribute was set to, and the context it was set in
+type AttributeRecord struct {
+	Value interface{}
+	// Context is the innermost context of the error when the value was set, empty if it had none
+	Context string
+	// Level is the number of contexts of the error when the value was set
+	Level int
+}
+
+// SetAttributeOrder sets the order attributes are formatted in
+func (r *Registry) SetAttributeOrder(order AttributeOrder) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.order = order
+}
+
+/*
+SetConflictPolicy sets the conflict policy of the given attributes, overriding the default one.
+
+  eerror.SetConflictPolicy(eerror.ConflictHistory, "user_id")
+*/
+func (r *Registry) SetConflictPolicy(policy ConflictPolicy, keys ...string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.conflicts == nil {
+		r.conflicts = make(map[string]ConflictPolicy)
+	}
+	for _, key := range keys {
+		r.conflicts[key] = policy
+	}
+}
+
+// SetDefaultConflictPolicy sets the conflict policy of the attributes without their own policy
+func (r *Registry) SetDefaultConflictPolicy(policy ConflictPolicy) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.defaultConflict = policy
+}
+
+// ConflictPolicy returns the conflict policy of an attribute
+func (r *Registry) ConflictPolicy(key string) ConflictPolicy {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if policy, ok := r.conflicts[key]; ok {
+		return policy
+	}
+	return r.defaultConflict
+}
+
+// SetAttributeOrder sets the order attributes are formatted in, in the default registry
+func SetAttributeOrder(order AttributeOrder) {
+	DefaultRegistry.SetAttributeOrder(order)
+}
+
+// SetConflictPolicy sets the conflict policy of the given attributes, in the default registry
+func SetConflictPolicy(policy ConflictPolicy, keys ...string) {
+	DefaultRegistry.SetConflictPolicy(policy, keys...)
+}
+
+// SetDefaultConflictPolicy sets the conflict policy of the attributes without their own policy, in the default registry
+func SetDefaultConflictPolicy(policy ConflictPolicy) {
+	DefaultRegistry.SetDefaultConflictPolicy(policy)
+}
+
+// AttributeHistory returns the values an attribute was set to, oldest first, when kept by the ConflictHistory policy
+func (e Eerror) AttributeHistory(key string) []AttributeRecord {
+	return append([]AttributeRecord{}, e.history[key]...)
+}
+
+// setAttribute sets an attribute according to its conflict policy, the attributes map being already copied
+func (e *Eerror) setAttribute(key string, value interface{}) {
+	policy := DefaultRegistry.ConflictPolicy(key)
+
+	if _, exists := e.attributes[key]; !exists {
+		e.order = append(e.order[:len(e.order):len(e.order)], key)
+	} else if policy == ConflictKeepFirst {
+		return
+	} else if policy == ConflictError {
+		e.WithCauses(NewError(E_ATTRIBUTECONFLICT, "attribute {key} already set", "key", key))
+		return
+	}
+
+	if policy == ConflictHistory {
+		record := AttributeRecord{Value: value, Level: len(e.contexts)}
+		if len(e.contexts) > 0 {
+			record.Context = e.contexts[len(e.contexts)-1]
+		}
+
+		history := make(map[string][]AttributeRecord, len(e.history)+1)
+		for k, records := range e.history {
+			history[k] = records
+		}
+		history[key] = append(history[key][:len(history[key]):len(history[key])], record)
+		e.history = history
+	}
+	e.attributes[key] = value
+}
+
+// attributeKeys returns the keys of the given attributes, in the order of the default registry
+func (e Eerror) attributeKeys(attributes map[string]interface{}) []string {
+	DefaultRegistry.mutex.RLock()
+	order := DefaultRegistry.order
+	DefaultRegistry.mutex.RUnlock()
+
+	if order != OrderInsertion {
+		return sortedKeys(attributes)
+	}
+
+	keys := make([]string, 0, len(attributes))
+	listed := make(map[string]bool, len(attributes))
+	for _, key := range e.order {
+		if _, ok := attributes[key]; ok && !listed[key] {
+			keys = append(keys, key)
+			listed[key] = true
+		}
+	}
+	// Attributes set without WithAttributes, such as parsed ones, follow sorted
+	for _, key := range sortedKeys(attributes) {
+		if !listed[key] {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// visibleHistory returns the previous values of the attributes kept by the ConflictHistory policy, resolved and redacted as formatted
+func (e Eerror) visibleHistory() map[string][]map[string]interface{} {
+	visible := make(map[string][]map[string]interface{}, len(e.history))
+	for key, records := range e.history {
+		if len(records) < 2 {
+			continue
+		}
+
+		secret := e.Visibility(key) == VisibilitySecret
+		for _, record := range records[:len(records)-1] {
+			value, ok := DefaultRegistry.redact(key, resolve(record.Value), secret)
+			if !ok {
+				continue
+			}
+			visible[key] = append(visible[key], map[string]interface{}{
+				"value":   value,
+				"context": record.Context,
+				"level":   record.Level,
+			})
+		}
+	}
+	return visible
+}
@@ -0,0 +1,95 @@
+package eerror
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestAttributeOrder ensures attributes are formatted in insertion order when asked
+func TestAttributeOrder(t *testing.T) {
+	SetAttributeOrder(OrderInsertion)
+	defer SetAttributeOrder(OrderSorted)
+
+	eerr := NewError(E_TESTERROR, "test error", "zone", "eu", "account", 42)
+	eerr.WithAttributes("method", "GET", "zone", "us")
+	eerr.attributes = copyAttributes(eerr.attributes, 1)
+	eerr.attributes["unordered"] = true
+
+	if keys := eerr.attributeKeys(eerr.attributes); strings.Join(keys, ",") != "stacktrace,zone,account,method,unordered" {
+		t.Error("Attributes should be ordered by first insertion\n", keys)
+	}
+	if formatted := eerr.Error(); strings.Index(formatted, "zone: us") > strings.Index(formatted, "account: (int)42") {
+		t.Error("Attributes should be formatted in insertion order\n", formatted)
+	}
+}
+
+// TestConflictPolicy ensures attributes already set are handled according to their conflict policy
+func TestConflictPolicy(t *testing.T) {
+	SetConflictPolicy(ConflictKeepFirst, "test_first")
+	SetConflictPolicy(ConflictError, "test_strict")
+	SetConflictPolicy(ConflictHistory, "test_user")
+	t.Cleanup(func() {
+		SetConflictPolicy(ConflictOverwrite, "test_first", "test_strict", "test_user")
+	})
+
+	eerr := NewError(E_TESTERROR, "test error", "test_first", 1, "test_strict", 1, "test_user", 1, "test_other", 1)
+	eerr.InContext("handler")
+	eerr.WithAttributes("test_first", 2, "test_user", 2, "test_other", 2)
+
+	attributes := eerr.GetAttributes()
+	if attributes["test_first"] != 1 || attributes["test_user"] != 2 || attributes["test_other"] != 2 {
+		t.Error("Attributes should be kept or overwritten according to their policy\n", attributes)
+	}
+
+	if history := eerr.AttributeHistory("test_user"); len(history) != 2 || history[0] != (AttributeRecord{1, "", 0}) || history[1] != (AttributeRecord{2, "handler", 1}) {
+		t.Error("Attribute history should record each value and its context\n", history)
+	}
+	if formatted := eerr.Error(); strings.Count(formatted, "test_user") != 1 {
+		t.Error("Previous values shouldn't be formatted\n", formatted)
+	}
+	for key := range From(eerr.Error()).GetAttributes() {
+		if strings.Contains(key, "@") {
+			t.Error("Parsed errors shouldn't hold previous values as attributes\n", key)
+		}
+	}
+	if history := eerr.Map()["history"].(map[string][]map[string]interface{}); len(history) != 1 || history["test_user"][0]["value"] != 1 {
+		t.Error("Previous values should be mapped\n", history)
+	}
+
+	eerr.WithAttribute("test_strict", 2)
+	if causes := eerr.Causes(); eerr.GetAttributes()["test_strict"] != 1 || len(causes) != 1 || causes[0].Id() != E_ATTRIBUTECONFLICT {
+		t.Error("Conflicts on attributes with the error policy should keep the first value, and be recorded as a cause\n", eerr.GetAttributes(), causes)
+	}
+}
+
+// TestConflictPolicyInternals ensures attributes set by the package itself ignore the conflict policy
+func TestConflictPolicyInternals(t *testing.T) {
+	SetDefaultConflictPolicy(ConflictError)
+	t.Cleanup(func() {
+		SetDefaultConflictPolicy(ConflictOverwrite)
+	})
+
+	err := (func() (err error) {
+		defer Recover(&err)
+		panic(fmt.Errorf("test error"))
+	})()
+
+	eerr := From(err)
+	if len(eerr.Causes()) != 0 {
+		t.Error("Recovering a panic shouldn't conflict with the attributes of the converted error\n", eerr.Causes())
+	}
+	if _, ok := eerr.GetAttributes()["panic"]; !ok {
+		t.Error("Recovered panic should hold its panic attribute\n", eerr.GetAttributes())
+	}
+}
+
+// TestConflictZeroRegistry ensures conflict policies can be set on a zero-value registry
+func TestConflictZeroRegistry(t *testing.T) {
+	registry := &Registry{}
+	registry.SetConflictPolicy(ConflictKeepFirst, "user_id")
+
+	if registry.ConflictPolicy("user_id") != ConflictKeepFirst || registry.ConflictPolicy("other") != ConflictOverwrite {
+		t.Error("Zero-value registry should hold conflict policies\n", registry.ConflictPolicy("user_id"))
+	}
+}
@@ -0,0 +1,91 @@
+package eerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type fieldsKey struct{}
+
+// fields holds the attributes of a context as key/value pairs, in the order they were first set
+type fields []interface{}
+
+/*
+WithFields returns a context holding attributes, merged into the errors created through NewErrorCtx and FromCtx.
+Fields already held by the context are kept, unless set again.
+
+  ctx = eerror.WithFields(ctx, "request_id", requestID, "tenant", tenant)
+*/
+func WithFields(ctx context.Context, attributeKeyValPairs ...interface{}) context.Context {
+	held, _ := ctx.Value(fieldsKey{}).(fields)
+	held = append(fields{}, held...)
+
+	for i := 0; i < len(attributeKeyValPairs); i += 2 {
+		key, ok := attributeKeyValPairs[i].(string)
+		if !ok {
+			key = fmt.Sprint(attributeKeyValPairs[i])
+		}
+
+		var value interface{} = nil
+		if len(attributeKeyValPairs) > i+1 {
+			value = attributeKeyValPairs[i+1]
+		}
+		held = held.set(key, value)
+	}
+	return context.WithValue(ctx, fieldsKey{}, held)
+}
+
+// set sets a field, replacing its value if already held. Fields aren't attributes yet: no snapshot nor conflict policy applies
+func (f fields) set(key string, value interface{}) fields {
+	for i := 0; i < len(f); i += 2 {
+		if f[i] == key {
+			f[i+1] = value
+			return f
+		}
+	}
+	return append(f, key, value)
+}
+
+// NewErrorCtx instanciates a new enhanced error as NewError does, with the attributes held by the context, overwritten by the given ones
+func NewErrorCtx(ctx context.Context, identifier, message string, attributeKeyValPairs ...interface{}) Eerror {
+	e := NewError(identifier, message, attributeKeyValPairs...)
+	e.withFields(ctx)
+	return e
+}
+
+/*
+FromCtx converts any error as From does, with the attributes held by the context, not overwriting the error ones.
+A context error (cancellation or deadline) is replaced by the context cancellation cause, if any.
+
+  ctx, cancel := context.WithCancelCause(ctx)
+  cancel(eerror.NewError(E_SHUTDOWN, "server shutting down"))
+
+  eerror.FromCtx(ctx, ctx.Err()).Id() // E_SHUTDOWN
+*/
+func FromCtx(ctx context.Context, err interface{}) Eerror {
+	if stdErr, ok := err.(error); ok && (errors.Is(stdErr, context.Canceled) || errors.Is(stdErr, context.DeadlineExceeded)) {
+		if cause := context.Cause(ctx); cause != nil && cause != ctx.Err() {
+			err = cause
+		}
+	}
+
+	e := From(err)
+	e.withFields(ctx)
+	return e
+}
+
+// withFields sets the attributes held by the context, in the order they were set, not overwriting the error ones
+func (e *Eerror) withFields(ctx context.Context) {
+	held, _ := ctx.Value(fieldsKey{}).(fields)
+
+	pairs := make([]interface{}, 0, len(held))
+	for i := 0; i < len(held); i += 2 {
+		if _, ok := e.attributes[held[i].(string)]; !ok {
+			pairs = append(pairs, held[i], held[i+1])
+		}
+	}
+	if len(pairs) > 0 {
+		e.withInternalAttributes(pairs...)
+	}
+}
@@ -0,0 +1,75 @@
+package eerror
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestContextFields ensures context attributes are merged into created errors
+func TestContextFields(t *testing.T) {
+	ctx := WithFields(context.Background(), "request_id", "request-42", "tenant", "acme")
+	ctx = WithFields(ctx, "tenant", "globex", "user", 42)
+
+	eerr := NewErrorCtx(ctx, E_TESTERROR, "This is a test error", "user", 43)
+	attributes := eerr.GetAttributes()
+	if attributes["request_id"] != "request-42" || attributes["tenant"] != "globex" || attributes["user"] != 43 {
+		t.Error("Created error should hold the context attributes, overwritten by explicit ones\n", attributes)
+	}
+
+	eerr = FromCtx(ctx, NewError(E_TESTERROR, "This is a test error", "user", 44))
+	if attributes := eerr.GetAttributes(); attributes["request_id"] != "request-42" || attributes["user"] != 44 {
+		t.Error("Converted error should hold the context attributes, without overwriting its own\n", attributes)
+	}
+
+	for i := 0; i < 8; i++ {
+		if order := strings.Join(NewErrorCtx(ctx, E_TESTERROR, "This is a test error").order, ","); order != "stacktrace,request_id,tenant,user" {
+			t.Fatal("Context attributes should be set in the order they were given\n", order)
+		}
+	}
+}
+
+// TestContextFieldsConflictPolicy ensures context attributes are merged regardless of the conflict policy
+func TestContextFieldsConflictPolicy(t *testing.T) {
+	t.Cleanup(func() {
+		SetDefaultConflictPolicy(ConflictOverwrite)
+	})
+
+	for _, policy := range []ConflictPolicy{ConflictKeepFirst, ConflictError} {
+		SetDefaultConflictPolicy(policy)
+
+		ctx := WithFields(context.Background(), "tenant", "acme", "user", 42)
+		ctx = WithFields(ctx, "tenant", "globex")
+
+		eerr := NewErrorCtx(ctx, E_TESTERROR, "This is a test error", "user", 43)
+		if attributes := eerr.GetAttributes(); attributes["tenant"] != "globex" || attributes["user"] != 43 || len(eerr.Causes()) != 0 {
+			t.Error("Context attributes should be set again, and overwritten by explicit ones, without conflicting (policy, attributes, causes)\n", policy, attributes, eerr.Causes())
+		}
+
+		eerr = FromCtx(ctx, NewError(E_TESTERROR, "This is a test error", "user", 44))
+		if attributes := eerr.GetAttributes(); attributes["tenant"] != "globex" || attributes["user"] != 44 || len(eerr.Causes()) != 0 {
+			t.Error("Converted error should hold the context attributes without conflicting (policy, attributes, causes)\n", policy, attributes, eerr.Causes())
+		}
+	}
+}
+
+// TestContextCause ensures context errors are replaced by their cancellation cause
+func TestContextCause(t *testing.T) {
+	cause := NewError(E_TESTERROR, "shutting down")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(cause)
+
+	if eerr := FromCtx(ctx, fmt.Errorf("rpc: %w", ctx.Err())); !eerr.Is(cause) {
+		t.Error("Context error should be replaced by its cancellation cause\n", eerr)
+	}
+	if eerr := FromCtx(ctx, Wrap(ctx.Err(), "calling {service}", "service", "billing")); !eerr.Is(cause) {
+		t.Error("Enhanced context error should be replaced by its cancellation cause\n", eerr)
+	}
+
+	ctx, cancel = context.WithCancelCause(context.Background())
+	cancel(nil)
+	if eerr := FromCtx(ctx, ctx.Err()); !eerr.Is(context.Canceled) {
+		t.Error("Context error without cause should be kept\n", eerr)
+	}
+}
@@ -0,0 +1,361 @@
+/*
+Package eerrorcheck defines an analyzer checking enhanced errors call sites.
+
+It reports:
+ - odd numbers of attribute key/value arguments, and attribute keys not being strings
+ - identifiers given to NewError, NewErrorCtx, Define and On not being constants
+ - identifiers declared twice, in the analyzed package or its dependencies
+ - enhanced errors returned by a call but ignored
+ - identifiers missing from the error catalog, when given one through the -catalog flag
+ - catalog identifiers left unhandled by Handle chains without Default, and Match chains ending with Value
+
+It is runnable as a standalone command, or as a vet tool, through cmd/eerror-vet.
+*/
+package eerrorcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/bLuka/EnhancedError/internal/catalog"
+)
+
+const eerrorPath = "github.com/bLuka/EnhancedError"
+
+// Analyzer reports mistakes in enhanced errors call sites
+var Analyzer = &analysis.Analyzer{
+	Name:      "eerrorcheck",
+	Doc:       "check enhanced errors call sites: attribute key/value pairs, identifiers and ignored errors",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes: []analysis.Fact{new(declaredIdentifiers)},
+	Run:       run,
+}
+
+var catalogPath string
+
+func init() {
+	Analyzer.Flags.StringVar(&catalogPath, "catalog", "", "error catalog file identifiers are checked against")
+}
+
+// declaredIdentifiers lists the identifiers declared by a package through Define, exported to its importers
+type declaredIdentifiers struct {
+	Identifiers []string
+}
+
+func (*declaredIdentifiers) AFact() {}
+
+func (f *declaredIdentifiers) String() string {
+	return fmt.Sprintf("declaredIdentifiers(%v)", f.Identifiers)
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	var known map[string]bool
+	if catalogPath != "" {
+		errors, err := catalog.Read(catalogPath)
+		if err != nil {
+			return nil, err
+		}
+		known = errors.Identifiers()
+	}
+
+	dependencies := make(map[string]string)
+	for _, fact := range pass.AllPackageFacts() {
+		for _, identifier := range fact.Fact.(*declaredIdentifiers).Identifiers {
+			dependencies[identifier] = fact.Package.Path()
+		}
+	}
+	declared := make(map[string]token.Pos)
+	dispatched := make(map[*ast.CallExpr]bool)
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.ExprStmt)(nil), (*ast.CallExpr)(nil)}, func(n ast.Node) {
+		if stmt, ok := n.(*ast.ExprStmt); ok {
+			checkIgnored(pass, stmt)
+			return
+		}
+
+		call := n.(*ast.CallExpr)
+		fn := calledFunction(pass, call)
+		if fn == nil {
+			return
+		}
+		signature := fn.Type().(*types.Signature)
+		if known != nil && !dispatched[call] {
+			checkDispatch(pass, call, known, dispatched)
+		}
+
+		for i := 0; i < signature.Params().Len() && i < len(call.Args); i++ {
+			param := signature.Params().At(i)
+			switch {
+			case param.Name() == "identifier" && createsOrHandles(pass, call, fn):
+				identifier, ok := checkIdentifier(pass, call.Args[i], known)
+				if ok && fn.Name() == "Define" {
+					checkDuplicate(pass, call.Args[i], identifier, declared, dependencies)
+				}
+			case param.Name() == "attributeKeyValPairs" && signature.Variadic() && i == signature.Params().Len()-1:
+				checkAttributes(pass, call, i)
+			}
+		}
+	})
+
+	if len(declared) > 0 {
+		fact := &declaredIdentifiers{}
+		for identifier := range declared {
+			fact.Identifiers = append(fact.Identifiers, identifier)
+		}
+		sort.Strings(fact.Identifiers)
+		pass.ExportPackageFact(fact)
+	}
+	return nil, nil
+}
+
+// createsOrHandles reports whether the called function creates or handles errors by their identifier, rather than looking it up
+func createsOrHandles(pass *analysis.Pass, call *ast.CallExpr, fn *types.Func) bool {
+	switch fn.Name() {
+	case "NewError", "NewErrorCtx", "Define":
+		return true
+	case "On":
+		return dispatchMethod(pass, call) == "On"
+	}
+	return false
+}
+
+// calledFunction returns the enhanced errors package function or method called, if any
+func calledFunction(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	fun := ast.Unparen(call.Fun)
+	switch index := fun.(type) {
+	case *ast.IndexExpr:
+		fun = index.X
+	case *ast.IndexListExpr:
+		fun = index.X
+	}
+
+	var ident *ast.Ident
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return nil
+	}
+
+	fn, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != eerrorPath {
+		return nil
+	}
+	return fn
+}
+
+func checkIgnored(pass *analysis.Pass, stmt *ast.ExprStmt) {
+	call, ok := ast.Unparen(stmt.X).(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	if named, ok := pass.TypesInfo.TypeOf(call).(*types.Named); ok && isEerror(named) {
+		pass.Reportf(call.Pos(), "enhanced error returned by %s is ignored", types.ExprString(call.Fun))
+	}
+}
+
+func checkIdentifier(pass *analysis.Pass, arg ast.Expr, known map[string]bool) (string, bool) {
+	value := pass.TypesInfo.Types[arg].Value
+	if value == nil || value.Kind() != constant.String {
+		pass.Reportf(arg.Pos(), "identifier %s should be a constant", types.ExprString(arg))
+		return "", false
+	}
+
+	identifier := constant.StringVal(value)
+	if known != nil && !known[identifier] {
+		diagnostic := analysis.Diagnostic{
+			Pos:     arg.Pos(),
+			End:     arg.End(),
+			Message: fmt.Sprintf("identifier %s is missing from the error catalog", identifier),
+		}
+		if closest := closestIdentifier(identifier, known); closest != "" {
+			diagnostic.Message += fmt.Sprintf(", did you mean %s?", closest)
+			if literal, ok := arg.(*ast.BasicLit); ok {
+				diagnostic.SuggestedFixes = []analysis.SuggestedFix{{
+					Message:   "Replace by " + closest,
+					TextEdits: []analysis.TextEdit{{Pos: literal.Pos(), End: literal.End(), NewText: []byte(strconv.Quote(closest))}},
+				}}
+			}
+		}
+		pass.Report(diagnostic)
+	}
+	return identifier, true
+}
+
+func checkDuplicate(pass *analysis.Pass, arg ast.Expr, identifier string, declared map[string]token.Pos, dependencies map[string]string) {
+	if pos, ok := declared[identifier]; ok {
+		pass.Reportf(arg.Pos(), "identifier %s already declared at %s", identifier, pass.Fset.Position(pos))
+		return
+	}
+	if path, ok := dependencies[identifier]; ok {
+		pass.Reportf(arg.Pos(), "identifier %s already declared by package %s", identifier, path)
+	}
+	declared[identifier] = arg.Pos()
+}
+
+func checkAttributes(pass *analysis.Pass, call *ast.CallExpr, first int) {
+	if call.Ellipsis.IsValid() || len(call.Args) <= first {
+		return
+	}
+
+	pairs := call.Args[first:]
+	for i := 0; i < len(pairs); i += 2 {
+		if t := pass.TypesInfo.TypeOf(pairs[i]); t != nil {
+			if basic, ok := t.Underlying().(*types.Basic); !ok || basic.Info()&types.IsString == 0 {
+				pass.Reportf(pairs[i].Pos(), "attribute key %s should be a string", types.ExprString(pairs[i]))
+			}
+		}
+	}
+
+	if len(pairs)%2 != 0 {
+		last := pairs[len(pairs)-1]
+		pass.Report(analysis.Diagnostic{
+			Pos:     last.Pos(),
+			End:     last.End(),
+			Message: fmt.Sprintf("odd number of attribute key/value arguments, attribute %s has no value", types.ExprString(last)),
+			SuggestedFixes: []analysis.SuggestedFix{{
+				Message:   "Set a nil value",
+				TextEdits: []analysis.TextEdit{{Pos: last.End(), End: last.End(), NewText: []byte(", nil")}},
+			}},
+		})
+	}
+}
+
+// checkDispatch reports the catalog identifiers left unhandled by a Handle or Match chain, given its last call
+func checkDispatch(pass *analysis.Pass, call *ast.CallExpr, known map[string]bool, dispatched map[*ast.CallExpr]bool) {
+	last := dispatchMethod(pass, call)
+	if last == "" {
+		return
+	}
+
+	var identifiers, kinds []string
+	for chain := call; ; {
+		dispatched[chain] = true
+
+		switch dispatchMethod(pass, chain) {
+		case "On", "OnKind":
+			if len(chain.Args) == 0 {
+				return
+			}
+			value := pass.TypesInfo.Types[chain.Args[0]].Value
+			if value == nil || value.Kind() != constant.String {
+				return
+			}
+			if dispatchMethod(pass, chain) == "On" {
+				identifiers = append(identifiers, constant.StringVal(value))
+			} else {
+				kinds = append(kinds, constant.StringVal(value))
+			}
+		case "":
+			return
+		}
+
+		selector, ok := ast.Unparen(chain.Fun).(*ast.SelectorExpr)
+		if !ok {
+			return
+		}
+		receiver, ok := ast.Unparen(selector.X).(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		if fn := calledFunction(pass, receiver); fn != nil && (fn.Name() == "Handle" || fn.Name() == "Match") {
+			if (fn.Name() == "Handle" && last == "Default") || (fn.Name() == "Match" && last != "Value") {
+				return
+			}
+			break
+		}
+		chain = receiver
+	}
+
+	var unhandled []string
+	for identifier := range known {
+		handled := false
+		for _, handledIdentifier := range identifiers {
+			handled = handled || identifier == handledIdentifier
+		}
+		for _, kind := range kinds {
+			handled = handled || identifier == kind || strings.HasPrefix(identifier, kind+".")
+		}
+		if !handled {
+			unhandled = append(unhandled, identifier)
+		}
+	}
+	if len(unhandled) > 0 {
+		sort.Strings(unhandled)
+		pass.Reportf(call.Pos(), "identifiers left unhandled: %s", strings.Join(unhandled, ", "))
+	}
+}
+
+// dispatchMethod returns the name of the Handler or Matcher method called, if any
+func dispatchMethod(pass *analysis.Pass, call *ast.CallExpr) string {
+	if _, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); !ok {
+		return ""
+	}
+	fn := calledFunction(pass, call)
+	if fn == nil {
+		return ""
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return ""
+	}
+	pointer, ok := recv.Type().(*types.Pointer)
+	if !ok {
+		return ""
+	}
+	named, ok := pointer.Elem().(*types.Named)
+	if !ok || (named.Obj().Name() != "Handler" && named.Obj().Name() != "Matcher") {
+		return ""
+	}
+	return fn.Name()
+}
+
+func isEerror(named *types.Named) bool {
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == eerrorPath && obj.Name() == "Eerror"
+}
+
+// closestIdentifier returns the known identifier closest to the given one, if close enough to be a typo
+func closestIdentifier(identifier string, known map[string]bool) string {
+	closest, closestDistance := "", 3
+	for candidate := range known {
+		if distance := levenshtein(identifier, candidate); distance < closestDistance || (distance == closestDistance && candidate < closest) {
+			closest, closestDistance = candidate, distance
+		}
+	}
+	return closest
+}
+
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,19 @@
+package eerrorcheck
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// TestAnalyzer ensures mistakes in enhanced errors call sites are reported, with their suggested fixes
+func TestAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	if err := Analyzer.Flags.Set("catalog", filepath.Join(testdata, "catalog.json")); err != nil {
+		t.Fatal(err)
+	}
+	defer Analyzer.Flags.Set("catalog", "")
+
+	analysistest.RunWithSuggestedFixes(t, testdata, Analyzer, "a")
+}
@@ -0,0 +1,60 @@
+package a // want package:`declaredIdentifiers\(\[E_KNOWN E_SHARED\]\)`
+
+import (
+	"context"
+
+	eerror "github.com/bLuka/EnhancedError"
+
+	"b"
+)
+
+const E_KNOWN = "E_KNOWN"
+
+var identifier = "E_KNOWN"
+
+var ErrKnown = eerror.Define(E_KNOWN, "known error")
+
+var ErrDuplicate = eerror.Define(E_KNOWN, "duplicated error") // want `identifier E_KNOWN already declared at .*`
+
+var ErrShared = eerror.Define("E_SHARED", "shared error") // want `identifier E_SHARED already declared by package b`
+
+var _ = b.ErrShared
+
+func calls(attributes []interface{}) error {
+	eerror.NewError(E_KNOWN, "ignored") // want `enhanced error returned by eerror.NewError is ignored`
+
+	err := eerror.NewError(identifier, "variable identifier") // want `identifier identifier should be a constant`
+	err = eerror.NewError(E_KNOWN, "odd attributes", "key")   // want `odd number of attribute key/value arguments, attribute "key" has no value`
+	err = eerror.NewError(E_KNOWN, "spread attributes", attributes...)
+	err = eerror.NewError("E_KNOWM", "typo")             // want `identifier E_KNOWM is missing from the error catalog, did you mean E_KNOWN\?`
+	err.WithAttributes("key", 1, 2, 3)                   // want `attribute key 2 should be a string`
+	err = err.Derive("context", "key", "value", "other") // want `odd number of attribute key/value arguments, attribute "other" has no value`
+	err = ErrKnown.Instance("key", "value")
+	err.InContext("context")
+	err = eerror.NewErrorCtx(context.Background(), identifier, "variable identifier") // want `identifier identifier should be a constant`
+	return err
+}
+
+func lookups(registry *eerror.Registry, err eerror.Eerror) bool {
+	_, found := eerror.Lookup(identifier)
+	registry.SetSeverity(identifier, 1)
+	return found && registry.IsKind(identifier, "E_DB") && err.IsKind(identifier)
+}
+
+func dispatch(err error) {
+	eerror.Handle(err). // want `identifiers left unhandled: E_DB.TIMEOUT, E_SHARED`
+				On(E_KNOWN, func(eerr eerror.Eerror) {})
+
+	eerror.Handle(err).
+		On(E_KNOWN, func(eerr eerror.Eerror) {}).
+		Default(func(eerr eerror.Eerror) {})
+
+	_, _ = eerror.Match[int](err). // want `identifiers left unhandled: E_KNOWN`
+					OnKind("E_DB", 1).
+					On("E_SHARED", 2).
+					Value()
+
+	_ = eerror.Match[int](err).On(E_KNOWN, 1).Default(0)
+
+	(eerror.Handle(err).On)(E_KNOWN, func(eerr eerror.Eerror) {}) // want `identifiers left unhandled: E_DB.TIMEOUT, E_SHARED`
+}
@@ -0,0 +1,5 @@
+package b
+
+import eerror "github.com/bLuka/EnhancedError"
+
+var ErrShared = eerror.Define("E_SHARED", "shared error")
@@ -0,0 +1,63 @@
+package eerror
+
+import "context"
+
+type Eerror struct{}
+
+func (e Eerror) Error() string { return "" }
+
+func NewError(identifier, message string, attributeKeyValPairs ...interface{}) Eerror {
+	return Eerror{}
+}
+
+func NewErrorCtx(ctx context.Context, identifier, message string, attributeKeyValPairs ...interface{}) Eerror {
+	return Eerror{}
+}
+
+func From(e interface{}) Eerror { return Eerror{} }
+
+func (e Eerror) IsKind(kind string) bool { return false }
+
+func (e *Eerror) InContext(context string) {}
+
+func (e *Eerror) WithAttributes(attributeKeyValPairs ...interface{}) {}
+
+func (e Eerror) Derive(context string, attributeKeyValPairs ...interface{}) Eerror { return e }
+
+type Template struct{}
+
+func Define(identifier, message string) *Template { return &Template{} }
+
+func (t *Template) Instance(attributeKeyValPairs ...interface{}) Eerror { return Eerror{} }
+
+func Lookup(identifier string) (*Template, bool) { return nil, false }
+
+type Registry struct{}
+
+func (r *Registry) Define(identifier, message string) *Template { return &Template{} }
+
+func (r *Registry) IsKind(identifier, kind string) bool { return false }
+
+func (r *Registry) SetSeverity(identifier string, severity int) {}
+
+type Handler struct{}
+
+func Handle(err error) *Handler { return &Handler{} }
+
+func (h *Handler) On(identifier string, fn func(eerr Eerror)) *Handler { return h }
+
+func (h *Handler) OnKind(kind string, fn func(eerr Eerror)) *Handler { return h }
+
+func (h *Handler) Default(fn func(eerr Eerror)) {}
+
+type Matcher[T any] struct{}
+
+func Match[T any](err error) *Matcher[T] { return &Matcher[T]{} }
+
+func (m *Matcher[T]) On(identifier string, value T) *Matcher[T] { return m }
+
+func (m *Matcher[T]) OnKind(kind string, value T) *Matcher[T] { return m }
+
+func (m *Matcher[T]) Default(value T) T { return value }
+
+func (m *Matcher[T]) Value() (T, bool) { var value T; return value, false }
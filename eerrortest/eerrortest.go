@@ -0,0 +1,142 @@
+/*
+Package eerrortest provides helpers to test code producing enhanced errors.
+
+  func TestLogin(t *testing.T) {
+     err := login("alice", "hunter2")
+     eerrortest.AssertRedacted(t, err, "hunter2")
+  }
+*/
+package eerrortest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	eerror "github.com/bLuka/EnhancedError"
+)
+
+const eerrorPath = "github.com/bLuka/EnhancedError"
+
+/*
+AssertRedacted fails the test if a raw secret reaches any output of the error:
+Error, fmt verbs, JSON, Map, slog handlers, Render and Print for each audience, and localized messages of the default catalog.
+Checked secrets are the given ones, along with the values wrapped as eerror.Secret in the attributes of the error and its causes, even nested.
+*/
+func AssertRedacted(t testing.TB, err error, secrets ...string) {
+	t.Helper()
+
+	eerr := eerror.From(err)
+	secrets = append(secrets, secretValues(eerr)...)
+
+	for name, output := range outputs(eerr) {
+		for _, secret := range secrets {
+			if secret != "" && strings.Contains(output, secret) {
+				t.Errorf("Secret %q leaked through %s\n%s", secret, name, output)
+			}
+		}
+	}
+}
+
+// outputs formats the error through every output, by output name
+func outputs(eerr eerror.Eerror) map[string]string {
+	outputs := map[string]string{
+		"Error":           eerr.Error(),
+		"%v":              fmt.Sprintf("%v", eerr),
+		"%+v":             fmt.Sprintf("%+v", eerr),
+		"%#v":             fmt.Sprintf("%#v", eerr),
+		"Message":         eerr.Message(),
+		"PublicMessage":   eerr.PublicMessage(),
+		"JSON":            marshal(eerr),
+		"Map":             marshal(eerr.Map()),
+		"Render public":   marshal(eerror.Render(eerr, eerror.AudiencePublic)),
+		"Render internal": marshal(eerror.Render(eerr, eerror.AudienceInternal)),
+	}
+
+	var output bytes.Buffer
+	slog.New(slog.NewJSONHandler(&output, nil)).Error("error", "error", eerr)
+	outputs["slog JSON"] = output.String()
+
+	output.Reset()
+	slog.New(slog.NewTextHandler(&output, nil)).Error("error", "error", eerr)
+	outputs["slog text"] = output.String()
+
+	output.Reset()
+	eerror.Print(&output, eerr, eerror.AudiencePublic)
+	outputs["Print public"] = output.String()
+
+	output.Reset()
+	eerror.Print(&output, eerr, eerror.AudienceInternal)
+	outputs["Print internal"] = output.String()
+
+	for _, locale := range eerror.DefaultCatalog.Locales() {
+		outputs["MessageIn "+locale] = eerr.MessageIn(locale)
+	}
+	return outputs
+}
+
+func marshal(value interface{}) string {
+	marshaled, err := json.Marshal(value)
+	if err != nil {
+		return err.Error()
+	}
+	return string(marshaled)
+}
+
+// secretValues returns the formatted values wrapped as eerror.Secret in the attributes of the error and its causes,
+// including the ones nested in maps, slices, arrays, pointers and exported structure fields
+func secretValues(eerr eerror.Eerror) []string {
+	var secrets []string
+	visited := make(map[uintptr]bool)
+	for _, value := range eerr.GetAttributes() {
+		secrets = append(secrets, nestedSecretValues(reflect.ValueOf(value), visited)...)
+	}
+
+	for _, cause := range eerr.Causes() {
+		secrets = append(secrets, secretValues(cause)...)
+	}
+	return secrets
+}
+
+func nestedSecretValues(value reflect.Value, visited map[uintptr]bool) []string {
+	if !value.IsValid() {
+		return nil
+	}
+	if value.Type().PkgPath() == eerrorPath && strings.HasPrefix(value.Type().Name(), "Secret[") {
+		if !value.CanInterface() {
+			return nil
+		}
+		return []string{fmt.Sprint(value.MethodByName("Reveal").Call(nil)[0].Interface())}
+	}
+
+	var secrets []string
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() || visited[value.Pointer()] {
+			return nil
+		}
+		visited[value.Pointer()] = true
+		return nestedSecretValues(value.Elem(), visited)
+	case reflect.Interface:
+		return nestedSecretValues(value.Elem(), visited)
+	case reflect.Map:
+		for entries := value.MapRange(); entries.Next(); {
+			secrets = append(secrets, nestedSecretValues(entries.Value(), visited)...)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			secrets = append(secrets, nestedSecretValues(value.Index(i), visited)...)
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).IsExported() {
+				secrets = append(secrets, nestedSecretValues(value.Field(i), visited)...)
+			}
+		}
+	}
+	return secrets
+}
@@ -0,0 +1,44 @@
+package eerrortest
+
+import (
+	"fmt"
+	"testing"
+
+	eerror "github.com/bLuka/EnhancedError"
+)
+
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+// TestAssertRedacted ensures leaked secrets are reported, whatever the output
+func TestAssertRedacted(t *testing.T) {
+	eerr := eerror.NewError("E_EERRORTEST", "login failed for {user}", "user", "alice", "password", "hunter2", "card", eerror.NewSecret(4242424242424242))
+	eerr.WithCauses(eerror.NewError("E_EERRORTEST_CAUSE", "cause", "pin", eerror.NewSecret("p1n-918273")))
+	eerr.WithCauses(eerror.NewError("E_EERRORTEST_NESTED", "nested", "credentials", map[string]interface{}{
+		"tokens": []eerror.Secret[string]{eerror.NewSecret("t0k-564738")},
+	}))
+
+	r := &recorder{TB: t}
+	AssertRedacted(r, eerr, "hunter2")
+	if len(r.failures) > 0 {
+		t.Error("Redacted secrets shouldn't be reported\n", r.failures)
+	}
+
+	r = &recorder{TB: t}
+	AssertRedacted(r, eerr, "alice")
+	if len(r.failures) == 0 {
+		t.Error("Leaked secrets should be reported\n")
+	}
+
+	if secrets := secretValues(eerr); len(secrets) != 3 || secrets[0] != "4242424242424242" || secrets[1] != "p1n-918273" || secrets[2] != "t0k-564738" {
+		t.Error("Secret values should be found in the error and its causes, even nested\n", secrets)
+	}
+}
@@ -8,9 +8,22 @@ Errors should always be composed by four major components:
  - attributes, essential for error reproducing purposes
 
 This package ensures the ability to manage errors following this pattern painlessly.
+
+Concurrency
+
+Enhanced errors are values: contexts and attributes are copied on write, and never updated once shared.
+An error stored in a cache or broadcast to several goroutines may thus be formatted and annotated concurrently,
+as long as each goroutine annotates its own copy (as returned by From, Dup or Derive) rather than a shared *Eerror.
+
+  go func(eerr eerror.Eerror) {
+     eerr.InContext("worker") // Only updates this goroutine's copy
+     log.Println(eerr)
+  }(sharedError)
 */
 package eerror
 
+import "time"
+
 /*
 Eerror type defines attributes available to build an enhanced error type.
 Enhanced errors allows strict error handling, ensure reproducable errors, and understandable error messages from context args.
@@ -22,6 +35,16 @@ type Eerror struct {
 	message    string
 	contexts   []string
 	attributes map[string]interface{}
+	causes     []Eerror
+	severity   Severity
+	class      Class
+
+	public       string
+	visibilities map[string]Visibility
+
+	order   []string
+	history map[string][]AttributeRecord
 
+	created   time.Time
 	_instance uint
 }
@@ -1,7 +1,11 @@
 package eerror
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"sync"
 
 	"testing"
 )
@@ -121,3 +125,163 @@ func TestAttribute(t *testing.T) {
 		t.Error("Third enhanced error should possess a single attribute labeled \"attribute\" containing an \"overwritten\" string\n", thirdErr.GetAttributes())
 	}
 }
+
+// TestInCallerContext ensures the caller function name is used as context
+func TestInCallerContext(t *testing.T) {
+	err := NewError(E_TESTERROR, "This is a test error")
+	err.InCallerContext()
+
+	if len(err.contexts) != 1 || err.contexts[0] != "EnhancedError.TestInCallerContext" {
+		t.Error("Context should be named after the calling function\n", err.contexts)
+	}
+}
+
+// TestAnnotate ensures deferred annotations only apply to non-nil errors
+func TestAnnotate(t *testing.T) {
+	failing := func(fail bool) (err error) {
+		defer Annotate(&err, "loading config %s", "app.conf")
+
+		if fail {
+			return fmt.Errorf("file not found")
+		}
+		return nil
+	}
+
+	if err := failing(false); err != nil {
+		t.Error("Annotate shouldn't create an error from a nil one\n", err)
+	}
+
+	err := failing(true)
+	eerr, ok := err.(Eerror)
+	if !ok {
+		t.Fatal("Annotate should convert the returned error to an enhanced error\n", err)
+	}
+	if len(eerr.contexts) != 1 || eerr.contexts[0] != "loading config app.conf" {
+		t.Error("Annotate should append the formatted context\n", eerr.contexts)
+	}
+}
+
+// TestCopyOnWrite ensures copies of an enhanced error never share their contexts or attributes
+func TestCopyOnWrite(t *testing.T) {
+	err := NewError(E_TESTERROR, "This is a test error")
+	err.InContext("first context")
+	err.InContext("second context")
+
+	branch := From(err)
+	branch.InContext("branch context")
+	branch.WithAttribute("branch attribute", true)
+
+	other := From(err)
+	other.InContext("other context")
+
+	if len(err.contexts) != 2 || len(err.GetAttributes()) != 1 {
+		t.Error("Original enhanced error shouldn't be updated by its copies\n", err.contexts, err.GetAttributes())
+	}
+	if branch.contexts[2] != "branch context" || other.contexts[2] != "other context" {
+		t.Error("Copies of an enhanced error shouldn't share their contexts\n", branch.contexts, other.contexts)
+	}
+	if _, ok := other.GetAttributes()["branch attribute"]; ok {
+		t.Error("Copies of an enhanced error shouldn't share their attributes\n", other.GetAttributes())
+	}
+
+	err.GetAttributes()["leaked attribute"] = true
+	if _, ok := err.GetAttributes()["leaked attribute"]; ok {
+		t.Error("GetAttributes should return a copy of the attributes\n", err.GetAttributes())
+	}
+}
+
+// TestConcurrentAnnotations ensures a shared enhanced error can be annotated and formatted from several goroutines
+func TestConcurrentAnnotations(t *testing.T) {
+	shared := NewError(E_TESTERROR, "This is a shared test error", "attribute", "value")
+	shared.InContext("shared context")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int, eerr Eerror) {
+			defer wg.Done()
+
+			eerr.InContext("worker context")
+			eerr.WithAttribute("worker", i)
+			derived := shared.Derive("derived context", "worker", i)
+
+			if derived.GetAttributes()["worker"] != i || eerr.GetAttributes()["worker"] != i {
+				t.Error("Concurrent annotations shouldn't leak between goroutines\n", derived.GetAttributes(), eerr.GetAttributes())
+			}
+			_ = shared.Error() + eerr.Error() + derived.Error()
+		}(i, shared)
+	}
+	wg.Wait()
+
+	if len(shared.contexts) != 1 || len(shared.GetAttributes()) != 2 {
+		t.Error("Shared enhanced error shouldn't be updated by concurrent annotations\n", shared.contexts, shared.GetAttributes())
+	}
+}
+
+// TestMessageTemplate ensures messages are rendered from attributes, while keeping their template
+func TestMessageTemplate(t *testing.T) {
+	err := NewError(E_TESTERROR, "user {user_id} lacks {permission} on {resource}", "user_id", 42, "permission", "write")
+
+	if expect := "user 42 lacks write on {resource}"; err.Message() != expect {
+		t.Error("Invalid rendered message\n", err.Message(), " | Expected:\n", expect)
+	}
+	if expect := E_TESTERROR + ": user 42 lacks write on {resource} ["; err.Error()[:len(expect)] != expect {
+		t.Error("Error should format the rendered message\n", err.Error(), " | Expected:\n", expect)
+	}
+
+	mapped := err.Map()
+	if mapped["message"] != err.Message() || mapped["template"] != "user {user_id} lacks {permission} on {resource}" {
+		t.Error("Map should expose both the rendered message and its template\n", mapped)
+	}
+}
+
+// TestMessageTemplateRoundTrip ensures errors parsed from the Error format keep the rendered message, their template being lost
+func TestMessageTemplateRoundTrip(t *testing.T) {
+	err := NewError(E_TESTERROR, "user {user_id} lacks {permission}", "user_id", 42, "permission", "write")
+
+	parsed := From(err.Error())
+	if parsed.Id() != E_TESTERROR || parsed.Message() != err.Message() {
+		t.Error("Parsed error should keep the identifier and the rendered message\n", parsed.Id(), parsed.Message())
+	}
+	if parsed.MessageTemplate() != err.Message() {
+		t.Error("Parsed error should hold the rendered message as its template\n", parsed.MessageTemplate())
+	}
+}
+
+// TestWrap ensures wrapped errors are related to the original error, in a rendered context
+func TestWrap(t *testing.T) {
+	stdError := fmt.Errorf("file not found")
+
+	eerr := Wrap(stdError, "opening {path}", "path", "app.conf")
+	if !eerr.Is(stdError) {
+		t.Error("Wrapped error should be related to the original error")
+	}
+	if len(eerr.contexts) != 1 || eerr.contexts[0] != "opening app.conf" {
+		t.Error("Wrapped error context should be rendered from the attributes\n", eerr.contexts)
+	}
+	if eerr.GetAttributes()["path"] != "app.conf" {
+		t.Error("Wrapped error should hold the given attributes\n", eerr.GetAttributes())
+	}
+}
+
+// TestUnwrap ensures errors.Is and errors.As match the original error of an enhanced error, and the template of an instance
+func TestUnwrap(t *testing.T) {
+	_, err := os.Open("/nonexistent/app.conf")
+	eerr := Wrap(err, "opening {path}", "path", "app.conf")
+
+	var pathErr *fs.PathError
+	if !errors.Is(eerr, fs.ErrNotExist) || !errors.As(eerr, &pathErr) || pathErr.Path != "/nonexistent/app.conf" {
+		t.Error("Wrapped errors should be matched through errors.Is and errors.As\n", eerr.Unwrap())
+	}
+	if wrapped := Wrap(fmt.Errorf("file not found"), ""); wrapped.Unwrap().Error() != "file not found" {
+		t.Error("Converted errors should unwrap to the original error\n", wrapped.Unwrap())
+	}
+
+	template := NewRegistry().Define(E_TESTERROR, "template error")
+	if instance := template.Instance(); !errors.Is(instance, template) {
+		t.Error("Template instances should be matched to their template through errors.Is\n")
+	}
+	if NewError(E_TESTERROR, "test error").Unwrap() != nil {
+		t.Error("New errors shouldn't unwrap to any error\n")
+	}
+}
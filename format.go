@@ -1,6 +1,7 @@
 package eerror
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"sort"
@@ -11,8 +12,16 @@ import (
 Error formats the error to a human readable string, as described by the error interface.
 
 Eg: `E_SOMEERROR: My error message (context 1; "context 2 with; (special) chars") [some attribute: some value, some other attribute: (int)1]
+
+The message is formatted as rendered from the attributes: errors parsed back from this format hold the rendered message as their template.
+Use Map or MarshalJSON to carry the message template along.
 */
 func (e Eerror) Error() string {
+	return e.format(e.visibleAttributes(AudienceInternal))
+}
+
+// format formats the error as Error does, from its already resolved and redacted attributes
+func (e Eerror) format(attributes map[string]interface{}) string {
 	const contextSeparator = "; "
 	var contextString string
 	var attributesString string
@@ -27,18 +36,12 @@ func (e Eerror) Error() string {
 		}
 		contextString += ")"
 	}
-	if len(e.attributes) > 0 {
+	if len(attributes) > 0 {
 		attributesString = " ["
 
-		keys := make([]string, 0)
-		for key := range e.attributes {
-			keys = append(keys, key)
-		}
-		sort.Strings(keys)
-
 		prependSeparator := false
-		for _, key := range keys {
-			value := e.attributes[key]
+		for _, key := range e.attributeKeys(attributes) {
+			value := attributes[key]
 
 			if prependSeparator {
 				attributesString += ", "
@@ -46,7 +49,7 @@ func (e Eerror) Error() string {
 			prependSeparator = true
 
 			var serializedValue = serialize(value)
-			if reflect.TypeOf(value).Kind() == reflect.String {
+			if value != nil && reflect.TypeOf(value).Kind() == reflect.String {
 				serializedValue = escapeString(serializedValue, "[]:,")
 			}
 			attributesString += escapeString(key, "[]:,") + ": " + serializedValue
@@ -54,18 +57,114 @@ func (e Eerror) Error() string {
 		attributesString += "]"
 	}
 
-	return fmt.Sprintf("%s: %s%s%s", escapeString(e.identifier, ":"), escapeString(e.message, ":()[]"), contextString, attributesString)
+	return fmt.Sprintf("%s: %s%s%s", escapeString(e.identifier, ":"), escapeString(interpolate(e.message, attributes), ":()[]"), contextString, attributesString)
 }
 
 // Map formats the error to a protocol-aware object, marshable without data loss
 func (e Eerror) Map() map[string]interface{} {
+	causes := make([]map[string]interface{}, len(e.causes))
+	for i, cause := range e.causes {
+		causes[i] = cause.Map()
+	}
+
+	attributes := e.visibleAttributes(AudienceInternal)
 	return map[string]interface{}{
-		"error":      e.Error(),
+		"error":      e.format(attributes),
 		"code":       e.identifier,
-		"message":    e.message,
-		"contexts":   e.contexts,
-		"attributes": e.attributes,
+		"message":    interpolate(e.message, attributes),
+		"template":   e.message,
+		"contexts":   append([]string{}, e.contexts...),
+		"attributes": attributes,
+		"causes":     causes,
+		"history":    e.visibleHistory(),
+		"severity":   e.Severity().String(),
+		"class":      e.Class().String(),
+	}
+}
+
+// MarshalJSON marshals the error as formatted by Map
+func (e Eerror) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.Map())
+}
+
+// GoString formats the error for the %#v verb, as Error does, not to leak redacted attributes
+func (e Eerror) GoString() string {
+	return fmt.Sprintf("eerror.Eerror(%q)", e.Error())
+}
+
+/*
+Message renders the error message, replacing each "{attribute}" placeholder by the value of the matching attribute.
+Placeholders without matching attribute are left untouched, and secret attributes are redacted.
+
+  err := NewError(E_PERMISSIONDENIED, "user {user_id} lacks {permission}", "user_id", 42, "permission", "write")
+  err.Message() // "user 42 lacks write"
+*/
+func (e Eerror) Message() string {
+	return interpolate(e.message, e.visibleAttributes(AudienceInternal))
+}
+
+// MessageTemplate returns the error message as given, before any placeholder replacement. Useful to group errors by message.
+// Errors parsed from the Error format only know their rendered message, returned instead
+func (e Eerror) MessageTemplate() string {
+	return e.message
+}
+
+func interpolate(template string, attributes map[string]interface{}) string {
+	if strings.IndexByte(template, '{') == -1 {
+		return template
+	}
+
+	var rendered strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start == -1 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end == -1 {
+			break
+		}
+		end += start
+
+		rendered.WriteString(template[:start])
+		if value, ok := attributes[template[start+1:end]]; ok {
+			rendered.WriteString(fmt.Sprint(value))
+		} else {
+			rendered.WriteString(template[start : end+1])
+		}
+		template = template[end+1:]
 	}
+	rendered.WriteString(template)
+
+	return rendered.String()
+}
+
+// placeholders lists the attribute names of the placeholders of a message template, as interpolate replaces them
+func placeholders(template string) []string {
+	var names []string
+	for {
+		start := strings.IndexByte(template, '{')
+		if start == -1 {
+			return names
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end == -1 {
+			return names
+		}
+		end += start
+
+		names = append(names, template[start+1:end])
+		template = template[end+1:]
+	}
+}
+
+func sortedKeys(attributes map[string]interface{}) []string {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 func escapeString(s string, chars string) string {
@@ -76,6 +175,9 @@ func escapeString(s string, chars string) string {
 }
 
 func serialize(value interface{}) string {
+	if value == nil {
+		return "<nil>"
+	}
 	var valueType = reflect.TypeOf(value)
 
 	if valueType.Kind() == reflect.String {
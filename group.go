@@ -0,0 +1,145 @@
+package eerror
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// GroupMode defines how a group reacts to its tasks failures
+type GroupMode int
+
+const (
+	// FailFast cancels the remaining tasks on the first failure
+	FailFast GroupMode = iota
+	// CollectAll runs every task to completion, collecting all failures
+	CollectAll
+)
+
+/*
+Group runs concurrent tasks under a common context, collecting their errors as enhanced errors.
+Each task error is put in a context naming the task, and task panics are converted into E_PANIC errors.
+Task errors caused by the group cancellation are classified apart from the failures.
+
+  group, ctx := eerror.NewGroup(ctx, eerror.FailFast)
+  for _, shard := range shards {
+     shard := shard
+     group.Go("fetch "+shard, func(ctx context.Context) error {
+        return fetch(ctx, shard)
+     })
+  }
+  if err := group.Wait(); err != nil {
+     return err
+  }
+*/
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	mode   GroupMode
+
+	wait  sync.WaitGroup
+	mutex sync.Mutex
+	tasks int
+
+	failures      []Eerror
+	cancellations []string
+}
+
+// NewGroup instanciates a group, returning the context given to its tasks
+func NewGroup(ctx context.Context, mode GroupMode) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	return &Group{
+		ctx:    ctx,
+		cancel: cancel,
+		mode:   mode,
+	}, ctx
+}
+
+// Go runs a named task in a new goroutine
+func (g *Group) Go(name string, task func(ctx context.Context) error) {
+	g.mutex.Lock()
+	g.tasks++
+	g.mutex.Unlock()
+
+	g.wait.Add(1)
+	go (func() {
+		defer g.wait.Done()
+
+		var err error
+		(func() {
+			defer RecoverContext(g.ctx, &err)
+			err = task(g.ctx)
+		})()
+
+		if err != nil {
+			g.fail(name, err)
+		}
+	})()
+}
+
+/*
+Wait waits for every task to complete, then returns the aggregated failures as an E_AGGREGATE enhanced error, or nil.
+The "failed" attribute lists the tasks that failed, in failure order,
+and the "cancelled" attribute lists the tasks that only failed because of the group cancellation.
+If no task failed on its own, the cancellation cause of the group context is returned instead.
+*/
+func (g *Group) Wait() error {
+	g.wait.Wait()
+	defer g.cancel(context.Canceled)
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if len(g.failures) == 0 && len(g.cancellations) == 0 {
+		return nil
+	}
+
+	var eerr Eerror
+	if len(g.failures) == 0 {
+		eerr = From(context.Cause(g.ctx))
+	} else {
+		failures := make([]error, len(g.failures))
+		failed := make([]string, len(g.failures))
+		for i, failure := range g.failures {
+			failures[i] = failure
+			failed[i] = failure.attributes["task"].(string)
+		}
+		eerr = Aggregate("{failures} of {tasks} tasks failed", failures...)
+		eerr.withInternalAttributes("tasks", g.tasks, "failed", failed)
+	}
+	if len(g.cancellations) > 0 {
+		eerr.withInternalAttributes("cancelled", append([]string{}, g.cancellations...))
+	}
+	return eerr
+}
+
+func (g *Group) fail(name string, err error) {
+	cancelled := g.ctx.Err() != nil && isCancellation(err)
+
+	eerr := From(err)
+	eerr.InContext(name)
+	eerr.withInternalAttributes("task", name)
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if cancelled {
+		g.cancellations = append(g.cancellations, name)
+		return
+	}
+	g.failures = append(g.failures, eerr)
+	if g.mode == FailFast && len(g.failures) == 1 {
+		g.cancel(eerr)
+	}
+}
+
+// isCancellation tests whether the error results from a context cancellation or deadline
+func isCancellation(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	eerr := From(err)
+	return eerr.Is(context.Canceled) || eerr.Is(context.DeadlineExceeded)
+}
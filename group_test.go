@@ -0,0 +1,76 @@
+package eerror
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestGroupFailFast ensures the first failure cancels the other tasks, classified apart
+func TestGroupFailFast(t *testing.T) {
+	rootFailure := NewError(E_TESTERROR, "root failure")
+
+	group, _ := NewGroup(context.Background(), FailFast)
+	group.Go("failing", func(ctx context.Context) error {
+		return rootFailure
+	})
+	group.Go("waiting", func(ctx context.Context) error {
+		<-ctx.Done()
+		return fmt.Errorf("waiting task: %w", ctx.Err())
+	})
+
+	eerr := From(group.Wait())
+	if eerr.Id() != E_AGGREGATE || len(eerr.Causes()) != 1 {
+		t.Fatal("Group should aggregate its failures\n", eerr)
+	}
+	if cause := eerr.Causes()[0]; !cause.Is(rootFailure) || cause.contexts[0] != "failing" || cause.GetAttributes()["task"] != "failing" {
+		t.Error("Group failure should be put in a context naming the task\n", cause)
+	}
+	if !strings.Contains(eerr.Error(), "failed: ([]string)[failing]") {
+		t.Error("Group error should name its failed tasks\n", eerr.Error())
+	}
+	if cancelled := eerr.GetAttributes()["cancelled"].([]string); len(cancelled) != 1 || cancelled[0] != "waiting" {
+		t.Error("Cancelled tasks should be classified apart from failures\n", cancelled)
+	}
+	if !eerr.Is(rootFailure) {
+		t.Error("Aggregated error should be related to its causes")
+	}
+}
+
+// TestGroupCollectAll ensures every failure is collected, including panics
+func TestGroupCollectAll(t *testing.T) {
+	group, _ := NewGroup(context.Background(), CollectAll)
+	for i := 0; i < 3; i++ {
+		i := i
+		group.Go(fmt.Sprint("task ", i), func(ctx context.Context) error {
+			switch i {
+			case 0:
+				return fmt.Errorf("failure")
+			case 1:
+				panic("task panic")
+			}
+			return ctx.Err()
+		})
+	}
+
+	eerr := From(group.Wait())
+	if len(eerr.Causes()) != 2 || eerr.Message() != "2 of 3 tasks failed" {
+		t.Fatal("Group should collect every failure\n", eerr.Message(), eerr.Causes())
+	}
+	panicked := false
+	for _, cause := range eerr.Causes() {
+		panicked = panicked || cause.Id() == E_PANIC
+	}
+	if !panicked {
+		t.Error("Task panics should be converted into E_PANIC errors\n", eerr.Causes())
+	}
+
+	group, _ = NewGroup(context.Background(), CollectAll)
+	group.Go("succeeding", func(ctx context.Context) error {
+		return nil
+	})
+	if err := group.Wait(); err != nil {
+		t.Error("Group without failure shouldn't return an error\n", err)
+	}
+}
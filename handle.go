@@ -0,0 +1,159 @@
+package eerror
+
+/*
+Handler dispatches an error to the first handler matching its identifier, or the identifier of an error in its chain:
+the enhanced errors it wraps, through %w or Unwrap methods, and its causes, recursively.
+
+  eerror.Handle(err).
+     On(E_NOTFOUND, func(eerr eerror.Eerror) { respond(404) }).
+     OnKind("E_DB", func(eerr eerror.Eerror) { respond(503) }).
+     Default(func(eerr eerror.Eerror) { respond(500) })
+*/
+type Handler struct {
+	chain   []Eerror
+	handled bool
+}
+
+// Handle instanciates a dispatcher for the given error. Nothing is dispatched for a nil error
+func Handle(err error) *Handler {
+	return &Handler{
+		chain: errorChain(err),
+	}
+}
+
+// On handles the error with fn if an error of its chain has the given identifier, and no previous handler matched
+func (h *Handler) On(identifier string, fn func(eerr Eerror)) *Handler {
+	return h.dispatch(func(eerr Eerror) bool {
+		return eerr.identifier == identifier
+	}, fn)
+}
+
+// OnKind handles the error with fn if an error of its chain is of the given kind, and no previous handler matched
+func (h *Handler) OnKind(kind string, fn func(eerr Eerror)) *Handler {
+	return h.dispatch(func(eerr Eerror) bool {
+		return eerr.IsKind(kind)
+	}, fn)
+}
+
+// Default handles the error with fn if no previous handler matched
+func (h *Handler) Default(fn func(eerr Eerror)) {
+	h.dispatch(func(eerr Eerror) bool {
+		return true
+	}, fn)
+}
+
+// Handled tests whether a handler matched the error
+func (h *Handler) Handled() bool {
+	return h.handled
+}
+
+func (h *Handler) dispatch(match func(eerr Eerror) bool, fn func(eerr Eerror)) *Handler {
+	if h.handled {
+		return h
+	}
+	for _, eerr := range h.chain {
+		if match(eerr) {
+			h.handled = true
+			fn(eerr)
+			break
+		}
+	}
+	return h
+}
+
+/*
+Matcher returns a value depending on the identifier of an error, or the identifier of an error in its chain, as Handler does.
+
+  status := eerror.Match[int](err).
+     On(E_NOTFOUND, 404).
+     OnKind("E_DB", 503).
+     Default(500)
+*/
+type Matcher[T any] struct {
+	chain   []Eerror
+	value   T
+	matched bool
+}
+
+// Match instanciates a matcher for the given error. Nothing is matched for a nil error
+func Match[T any](err error) *Matcher[T] {
+	return &Matcher[T]{
+		chain: errorChain(err),
+	}
+}
+
+// On selects the value if an error of the chain has the given identifier, and no previous value was selected
+func (m *Matcher[T]) On(identifier string, value T) *Matcher[T] {
+	return m.match(func(eerr Eerror) bool {
+		return eerr.identifier == identifier
+	}, value)
+}
+
+// OnKind selects the value if an error of the chain is of the given kind, and no previous value was selected
+func (m *Matcher[T]) OnKind(kind string, value T) *Matcher[T] {
+	return m.match(func(eerr Eerror) bool {
+		return eerr.IsKind(kind)
+	}, value)
+}
+
+// Default returns the selected value, or the given one if none was selected
+func (m *Matcher[T]) Default(value T) T {
+	if m.matched {
+		return m.value
+	}
+	return value
+}
+
+// Value returns the selected value, and whether one was selected
+func (m *Matcher[T]) Value() (T, bool) {
+	return m.value, m.matched
+}
+
+func (m *Matcher[T]) match(match func(eerr Eerror) bool, value T) *Matcher[T] {
+	if m.matched {
+		return m
+	}
+	for _, eerr := range m.chain {
+		if match(eerr) {
+			m.value, m.matched = value, true
+			break
+		}
+	}
+	return m
+}
+
+// errorChain lists the error, then recursively the enhanced errors it wraps, as errors.As finds them, and its causes
+func errorChain(err error) []Eerror {
+	if err == nil {
+		return nil
+	}
+
+	var chain []Eerror
+	var walk func(eerr Eerror)
+	var unwrap func(err error)
+	walk = func(eerr Eerror) {
+		chain = append(chain, eerr)
+		unwrap(eerr.Unwrap())
+		for _, cause := range eerr.causes {
+			walk(cause)
+		}
+	}
+	unwrap = func(err error) {
+		switch err := err.(type) {
+		case Eerror:
+			walk(err)
+		case *Eerror:
+			if err != nil {
+				walk(*err)
+			}
+		case interface{ Unwrap() []error }:
+			for _, wrapped := range err.Unwrap() {
+				unwrap(wrapped)
+			}
+		case interface{ Unwrap() error }:
+			unwrap(err.Unwrap())
+		}
+	}
+	walk(From(err))
+	return chain
+}
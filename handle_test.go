@@ -0,0 +1,61 @@
+package eerror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestHandle ensures errors are dispatched to the first matching handler, through the error chain
+func TestHandle(t *testing.T) {
+	dispatch := func(err error) string {
+		var handled string
+		Handle(err).
+			On(E_TESTERROR, func(eerr Eerror) { handled = "identifier" }).
+			OnKind("E_DB", func(eerr Eerror) { handled = "kind " + eerr.Id() }).
+			Default(func(eerr Eerror) { handled = "default" })
+		return handled
+	}
+
+	aggregated := Aggregate("aggregated", fmt.Errorf("unknown"), NewError("E_DB.TIMEOUT", "database timeout"))
+	for _, test := range []struct {
+		err      error
+		expected string
+	}{
+		{NewError(E_TESTERROR, "test error"), "identifier"},
+		{NewError("E_DB.CONSTRAINT.UNIQUE", "duplicated entry"), "kind E_DB.CONSTRAINT.UNIQUE"},
+		{aggregated, "kind E_DB.TIMEOUT"},
+		{fmt.Errorf("loading: %w", NewError(E_TESTERROR, "test error")), "identifier"},
+		{errors.Join(fmt.Errorf("unknown"), NewError("E_DB.TIMEOUT", "database timeout")), "kind E_DB.TIMEOUT"},
+		{fmt.Errorf("unknown"), "default"},
+		{nil, ""},
+	} {
+		if handled := dispatch(test.err); handled != test.expected {
+			t.Error("Invalid dispatch (error, result, expected)\n", test.err, handled, test.expected)
+		}
+	}
+
+	if Handle(fmt.Errorf("unknown")).On(E_TESTERROR, func(eerr Eerror) {}).Handled() {
+		t.Error("Unmatched error shouldn't be handled")
+	}
+}
+
+// TestMatch ensures values are selected from the error identifier
+func TestMatch(t *testing.T) {
+	status := func(err error) int {
+		return Match[int](err).
+			On(E_TESTERROR, 404).
+			OnKind("E_DB", 503).
+			Default(500)
+	}
+
+	if status(NewError(E_TESTERROR, "not found")) != 404 || status(NewError("E_DB.TIMEOUT", "timeout")) != 503 || status(fmt.Errorf("unknown")) != 500 {
+		t.Error("Invalid matched values")
+	}
+	if value, _ := Match[string](fmt.Errorf("loading: %w", NewError("E_DB.TIMEOUT", "timeout"))).OnKind("E_DB", "database").Value(); value != "database" {
+		t.Error("Wrapped errors should be matched\n", value)
+	}
+	if _, ok := Match[int](fmt.Errorf("unknown")).On(E_TESTERROR, 404).Value(); ok {
+		t.Error("Unmatched error shouldn't select a value")
+	}
+}
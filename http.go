@@ -0,0 +1,125 @@
+package eerror
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// RequestIDHeader is the request header holding the request identifier, attached to errors reported from HTTP handlers
+var RequestIDHeader = "X-Request-Id"
+
+// ProblemAudience is the audience errors are rendered for by WriteProblem. Setting AudienceInternal exposes internal details to clients
+var ProblemAudience = AudiencePublic
+
+// sensitiveHeaders lists the headers whose values are never attached to errors
+var sensitiveHeaders = []string{"authorization", "proxy-authorization", "cookie", "set-cookie", "token", "secret", "api-key", "apikey", "password"}
+
+// Reporter forwards an enhanced error to a logging or monitoring system
+type Reporter func(ctx context.Context, err Eerror)
+
+// LogReporter reports errors to the standard logger, prefixed by their severity
+func LogReporter(ctx context.Context, err Eerror) {
+	log.Printf("[%s] %s", err.Severity(), err)
+}
+
+/*
+RecoverHandler wraps an HTTP handler, converting its panics into E_PANIC enhanced errors.
+The error holds the request method, route, identifier and sanitized headers as attributes.
+It is given to the reporter (LogReporter if nil), then rendered to the client as an HTTP problem.
+
+  http.ListenAndServe(":8080", eerror.RecoverHandler(mux, eerror.LogReporter))
+*/
+func RecoverHandler(next http.Handler, reporter Reporter) http.Handler {
+	if reporter == nil {
+		reporter = LogReporter
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer (func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			eerr := fromPanic(recovered, r.Context())
+			eerr.WithAttributes(requestAttributes(r)...)
+
+			reporter(r.Context(), eerr)
+			WriteProblem(w, r, eerr, http.StatusInternalServerError)
+		})()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+/*
+WriteProblem renders an error as an HTTP problem (RFC 9457), in the language negotiated from the request Accept-Language header.
+The error is rendered for the ProblemAudience: its public message and attributes only, unless opted in for internal details.
+
+  {"type": "about:blank", "title": "Not Found", "status": 404, "code": "E_USER_NOT_FOUND", "detail": "user 42 not found", "attributes": {"user_id": 42}}
+*/
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error, status int) {
+	locale := DefaultCatalog.Negotiate(r.Header.Get("Accept-Language"))
+	rendering := DefaultCatalog.Render(err, ProblemAudience, locale)
+
+	problem := map[string]interface{}{
+		"type":   "about:blank",
+		"title":  http.StatusText(status),
+		"status": status,
+	}
+	for key, value := range rendering {
+		if key == "message" {
+			key = "detail"
+		}
+		problem[key] = value
+	}
+	if locale != "" {
+		w.Header().Set("Content-Language", locale)
+	}
+	if requestID := r.Header.Get(RequestIDHeader); requestID != "" {
+		problem["request_id"] = requestID
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+func requestAttributes(r *http.Request) []interface{} {
+	route := r.Pattern
+	if route == "" {
+		route = r.URL.Path
+	}
+
+	headers := make(map[string]string, len(r.Header))
+	for name, values := range r.Header {
+		if isSensitiveHeader(name) {
+			headers[name] = Redacted
+			continue
+		}
+		headers[name] = strings.Join(values, ", ")
+	}
+
+	return []interface{}{
+		"method", r.Method,
+		"route", route,
+		"request_id", r.Header.Get(RequestIDHeader),
+		"headers", headers,
+	}
+}
+
+func isSensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, sensitive := range sensitiveHeaders {
+		if strings.Contains(name, sensitive) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package eerror
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestRecoverHandler ensures handler panics are reported as enhanced errors, and rendered as HTTP problems
+func TestRecoverHandler(t *testing.T) {
+	var reported Eerror
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		panic("handler panic")
+	})
+	handler := RecoverHandler(mux, func(ctx context.Context, err Eerror) {
+		reported = err
+	})
+
+	request := httptest.NewRequest("GET", "/users/42", nil)
+	request.Header.Set("X-Request-Id", "request-42")
+	request.Header.Set("Authorization", "Bearer secret")
+	request.Header.Set("Accept", "application/json")
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	if reported.Id() != E_PANIC {
+		t.Fatal("Handler panic should be reported as an E_PANIC enhanced error\n", reported)
+	}
+	attributes := reported.GetAttributes()
+	if attributes["method"] != "GET" || attributes["route"] != "GET /users/{id}" || attributes["request_id"] != "request-42" {
+		t.Error("Reported error should hold the request attributes\n", attributes)
+	}
+	if headers := attributes["headers"].(map[string]string); headers["Authorization"] != "[REDACTED]" || headers["Accept"] != "application/json" {
+		t.Error("Reported error should hold the sanitized request headers\n", headers)
+	}
+
+	var problem map[string]interface{}
+	if err := json.NewDecoder(response.Body).Decode(&problem); err != nil {
+		t.Fatal("Response should be a JSON problem\n", err)
+	}
+	if response.Code != http.StatusInternalServerError || response.Header().Get("Content-Type") != "application/problem+json" {
+		t.Error("Response should be an internal server error problem\n", response.Code, response.Header())
+	}
+	if problem["code"] != E_PANIC || problem["status"] != float64(http.StatusInternalServerError) || problem["request_id"] != "request-42" {
+		t.Error("Invalid problem\n", problem)
+	}
+}
+
+// TestWriteProblem ensures problems are localized from the Accept-Language header
+func TestWriteProblem(t *testing.T) {
+	DefaultCatalog.Set("fr", E_TESTERROR_LOCALIZED, "utilisateur {user_id} introuvable")
+
+	request := httptest.NewRequest("GET", "/", nil)
+	request.Header.Set("Accept-Language", "fr-FR, en;q=0.5")
+	response := httptest.NewRecorder()
+	eerr := NewError(E_TESTERROR_LOCALIZED, "user {user_id} not found", "user_id", 42, "shard", "eu-1")
+	eerr.WithVisibility(VisibilityPublic, "user_id")
+	WriteProblem(response, request, eerr, http.StatusNotFound)
+
+	var problem map[string]interface{}
+	json.NewDecoder(response.Body).Decode(&problem)
+	if problem["detail"] != "utilisateur 42 introuvable" || response.Header().Get("Content-Language") != "fr" {
+		t.Error("Problem should be localized\n", problem, response.Header())
+	}
+	if attributes := problem["attributes"].(map[string]interface{}); len(attributes) != 1 || attributes["user_id"] != float64(42) {
+		t.Error("Problem should only hold public attributes\n", problem)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"runtime/debug"
+	"time"
 )
 
 const E_EXTERNALERROR = "E_EXTERNALERROR"
@@ -15,8 +16,16 @@ var errorParsedAttributes = []interface{}{
 /*
 From takes any parameter to convert it as an enhanced error.
 Returns the given parameter if it's already an enhanced error instance, or nil
+Well-known standard library errors (fs.ErrNotExist, context.Canceled, *net.OpError, ...) are given a meaningful identifier,
+along with the attributes describing them.
+Other errors are converted by the translators of the default registry.
 */
 func From(e interface{}) Eerror {
+	return DefaultRegistry.From(e)
+}
+
+// From converts any parameter as an enhanced error as the package From does, consulting the translators of the registry
+func (r *Registry) From(e interface{}) Eerror {
 	if eerr, ok := e.(Eerror); ok {
 		return eerr
 	}
@@ -24,10 +33,48 @@ func From(e interface{}) Eerror {
 		return *eerr
 	}
 	if ptr, ok := e.(*interface{}); ok {
-		return fromError(ptr)
+		return r.fromError(ptr)
+	}
+
+	return r.fromError(&e)
+}
+
+/*
+Wrap converts any error to an enhanced error as From does, in the given context and with potential attributes.
+The context is a message template, rendered from the given attributes. An empty context appends no context.
+
+  if _, err := os.Open(path); err != nil {
+     return eerror.Wrap(err, "opening {path}", "path", path)
+  }
+*/
+func Wrap(err interface{}, context string, attributeKeyValPairs ...interface{}) Eerror {
+	var attributes Eerror
+	attributes.WithAttributes(attributeKeyValPairs...)
+
+	eerr := From(err)
+	if context != "" {
+		eerr.InContext(interpolate(context, attributes.placeholderAttributes(context)))
 	}
+	eerr.WithAttributes(attributeKeyValPairs...)
+	return eerr
+}
+
+/*
+Unwrap returns the error an enhanced error was converted from by From or Wrap, or the template it was instanciated from.
+Allows errors.Is and errors.As to match the original error through its enhanced error.
 
-	return fromError(&e)
+  eerr := eerror.Wrap(err, "reading {path}", "path", path)
+  errors.Is(eerr, fs.ErrNotExist) // true if err was
+*/
+func (e Eerror) Unwrap() error {
+	parent := e.parent
+	if pointer, ok := parent.(*interface{}); ok {
+		parent = *pointer
+	}
+	if err, ok := parent.(error); ok {
+		return err
+	}
+	return nil
 }
 
 /*
@@ -36,13 +83,10 @@ Useful to test if an enhanced error instance was formed from the given instance
 
   const E_MY_ERROR_ID = "E_MY_ERROR_ID"
 
-  var standardError = eerror.NewError(E_MY_ERROR_ID, "Some error")
+  var standardError = eerror.Define(E_MY_ERROR_ID, "Some error")
 
   func errorFunction(myParameter interface{}) eerror.Eerror {
-     err := standardError.Copy()
-     err.WithAttribute("parameter", myParameter)
-
-     return err
+     return standardError.Instance("parameter", myParameter)
   }
 
   func main() {
@@ -90,10 +134,23 @@ func (e *Eerror) Is(instance interface{}, log ...interface{}) bool {
 			return true
 		}
 	}
-	return initial == instanceInitial
+	if initial == instanceInitial {
+		return true
+	}
+
+	for _, cause := range e.causes {
+		if cause.Is(instance) {
+			return true
+		}
+	}
+	return false
 }
 
-// Dup ensures a copy of a given enhanced error, reinstanciating contexts and attributes
+/*
+Dup ensures a copy of a given enhanced error, reinstanciating contexts and attributes
+As contexts and attributes are copied on write, a plain copy of an enhanced error value is already independent; Dup remains for explicitness.
+Attribute values themselves are shared between copies, unless captured according to their snapshot policy when set (see SetSnapshot).
+*/
 func (e Eerror) Dup() Eerror {
 	err := Eerror{
 		e.parent,
@@ -101,15 +158,19 @@ func (e Eerror) Dup() Eerror {
 		e.identifier,
 		e.message,
 		make([]string, len(e.contexts)),
-		make(map[string]interface{}, len(e.attributes)),
+		copyAttributes(e.attributes, 0),
+		append([]Eerror{}, e.causes...),
+		e.severity,
+		e.class,
+		e.public,
+		e.visibilities,
+		e.order,
+		e.history,
+		e.created,
 		e._instance,
 	}
 
 	copy(err.contexts, e.contexts)
-	for key, value := range e.attributes {
-		err.attributes[key] = value
-	}
-
 	return err
 }
 
@@ -120,13 +181,20 @@ func (e *Eerror) getInitialError() interface{} {
 		return parent.getInitialError()
 	}
 
+	if parent, ok := e.parent.(*Template); ok {
+		return parent
+	}
+
 	if e.parent != nil {
 		return *(e.parent.(*interface{}))
 	}
 	return e
 }
 
-func fromError(err *interface{}) Eerror {
+func (r *Registry) fromError(err *interface{}) Eerror {
+	if eerr, _, ok := r.translate(err); ok {
+		return eerr
+	}
 	if eerr, ok := parse(*err); ok {
 		return eerr
 	}
@@ -138,11 +206,19 @@ func fromError(err *interface{}) Eerror {
 		fmt.Sprint(*err),
 		[]string{},
 		make(map[string]interface{}, len(errorParsedAttributes)/2),
+		nil,
+		0,
+		0,
+		"",
+		nil,
+		nil,
+		nil,
+		time.Now(),
 		generateUniqueID(),
 	}
 
-	eerr.WithAttribute("stacktrace", string(debug.Stack()))
-	eerr.WithAttributes(
+	eerr.withInternalAttributes("stacktrace", string(debug.Stack()))
+	eerr.withInternalAttributes(
 		errorParsedAttributes...,
 	)
 	return eerr
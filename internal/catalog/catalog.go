@@ -0,0 +1,82 @@
+/*
+Package catalog describes error catalog files, shared by the eerror commands.
+An error catalog is a JSON file listing errors, with their identifier, message template, and required attributes.
+Errors whose template is declared by hand, such as migrated sentinel errors, name it so that it isn't generated again:
+
+  {
+     "errors": [
+        {
+           "id": "E_USER_NOT_FOUND",
+           "message": "user {user_id} not found",
+           "attributes": [{"name": "user_id", "type": "int64"}]
+        },
+        {
+           "id": "E_USER_LOCKED",
+           "message": "user locked",
+           "template": "ErrLocked"
+        }
+     ]
+  }
+*/
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Catalog describes the errors of an application
+type Catalog struct {
+	Errors []Error `json:"errors"`
+}
+
+// Error describes a single error of the catalog
+type Error struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name,omitempty"`
+	Message    string      `json:"message"`
+	Attributes []Attribute `json:"attributes,omitempty"`
+	// Template names the template variable declared by hand for the error, if any, not to be generated
+	Template string `json:"template,omitempty"`
+}
+
+// Attribute describes an attribute required by an error
+type Attribute struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+}
+
+// Read reads and validates an error catalog file
+func Read(path string) (Catalog, error) {
+	var catalog Catalog
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return catalog, err
+	}
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return catalog, fmt.Errorf("%s: %v", path, err)
+	}
+
+	identifiers := make(map[string]bool, len(catalog.Errors))
+	for _, e := range catalog.Errors {
+		if e.ID == "" {
+			return catalog, fmt.Errorf("%s: error without identifier", path)
+		}
+		if identifiers[e.ID] {
+			return catalog, fmt.Errorf("%s: identifier %s declared twice", path, e.ID)
+		}
+		identifiers[e.ID] = true
+	}
+	return catalog, nil
+}
+
+// Identifiers returns the set of identifiers declared in the catalog
+func (c Catalog) Identifiers() map[string]bool {
+	identifiers := make(map[string]bool, len(c.Errors))
+	for _, e := range c.Errors {
+		identifiers[e.ID] = true
+	}
+	return identifiers
+}
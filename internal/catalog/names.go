@@ -0,0 +1,56 @@
+package catalog
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ConstantName turns an identifier into a valid constant name, E_DB.TIMEOUT being declared as E_DB_TIMEOUT
+func ConstantName(identifier string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, identifier)
+}
+
+// TypeName returns the name suffixing generated symbols, E_USER_NOT_FOUND being declared as UserNotFound
+func (e Error) TypeName() string {
+	if e.Name != "" {
+		return e.Name
+	}
+
+	var name strings.Builder
+	for _, word := range Words(strings.TrimPrefix(e.ID, "E_")) {
+		name.WriteString(Capitalize(word))
+	}
+	return name.String()
+}
+
+// TemplateName returns the name of the template of the error: the declared one, or the one generated as ErrUserNotFound
+func (e Error) TemplateName() string {
+	if e.Template != "" {
+		return e.Template
+	}
+	return "Err" + e.TypeName()
+}
+
+// Words splits a name into its words, on any character other than letters and digits
+func Words(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Capitalize capitalizes a word, initialisms (id, url, ...) being upper-cased
+func Capitalize(word string) string {
+	switch word = strings.ToLower(word); word {
+	case "id", "url", "uri", "http", "json", "sql", "api", "ip":
+		return strings.ToUpper(word)
+	}
+	if word == "" {
+		return word
+	}
+	return strings.ToUpper(word[:1]) + word[1:]
+}
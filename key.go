@@ -0,0 +1,197 @@
+package eerror
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+Key is a typed attribute key, to set and read attributes without type assertions.
+
+  var UserID = eerror.Key[int64]("user_id")
+
+  UserID.Set(&eerr, 42)
+  if userID, ok := UserID.Get(err); ok {
+     ...
+  }
+*/
+type Key[T any] string
+
+// Name returns the attribute name of the key
+func (k Key[T]) Name() string {
+	return string(k)
+}
+
+// Set sets the attribute of the key on the error
+func (k Key[T]) Set(err *Eerror, value T) {
+	err.WithAttribute(string(k), value)
+}
+
+/*
+Get retrieves the attribute of the key from any error, converted as From does.
+Values which lost their type, through parsing ("(int64)42") or JSON decoding (float64, map[string]interface{}, ...),
+are coerced to the key type. Returns false if the attribute is missing, or cannot be coerced without loss.
+Secret values are never coerced, only read by keys of their own Secret type.
+*/
+func (k Key[T]) Get(err error) (T, bool) {
+	var zero T
+	if err == nil {
+		return zero, false
+	}
+
+	value, ok := From(err).attributes[string(k)]
+	if !ok {
+		return zero, false
+	}
+	return coerce[T](resolve(value))
+}
+
+// coerce converts a value to the given type, if possible without loss
+func coerce[T any](value interface{}) (T, bool) {
+	var coerced T
+	if typed, ok := value.(T); ok {
+		return typed, true
+	}
+	if _, ok := value.(secretValue); ok || value == nil {
+		return coerced, false
+	}
+
+	target := reflect.ValueOf(&coerced).Elem()
+	if number, ok := value.(json.Number); ok {
+		value = string(number)
+	}
+	if s, ok := value.(string); ok {
+		if !parseInto(target, s) {
+			return *new(T), false
+		}
+		return coerced, true
+	}
+	if convertNumber(reflect.ValueOf(value), target) {
+		return coerced, true
+	}
+
+	// Decoded JSON values, such as maps to be coerced to structures, are coerced through JSON
+	serialized, err := json.Marshal(value)
+	if err != nil || json.Unmarshal(serialized, &coerced) != nil {
+		return *new(T), false
+	}
+	return coerced, true
+}
+
+// parseInto parses a formatted value into the target, the type prefix of parsed attributes ("(int64)42") being ignored
+func parseInto(target reflect.Value, s string) bool {
+	if target.Kind() == reflect.String {
+		target.SetString(s)
+		return true
+	}
+	if end := strings.IndexByte(s, ')'); strings.HasPrefix(s, "(") && end != -1 {
+		s = s[end+1:]
+	}
+
+	if target.Type() == reflect.TypeOf(time.Duration(0)) {
+		duration, err := time.ParseDuration(s)
+		if err != nil {
+			return false
+		}
+		target.SetInt(int64(duration))
+		return true
+	}
+
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, target.Type().Bits())
+		if err != nil {
+			return false
+		}
+		target.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, target.Type().Bits())
+		if err != nil {
+			return false
+		}
+		target.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, target.Type().Bits())
+		if err != nil {
+			return false
+		}
+		target.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return false
+		}
+		target.SetBool(b)
+	default:
+		return json.Unmarshal([]byte(s), target.Addr().Interface()) == nil
+	}
+	return true
+}
+
+// convertNumber converts a number into a numeric target, failing on overflow or truncation
+func convertNumber(source, target reflect.Value) bool {
+	var f float64
+	switch source.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f = float64(source.Int())
+		switch target.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if target.OverflowInt(source.Int()) {
+				return false
+			}
+			target.SetInt(source.Int())
+			return true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if source.Int() < 0 || target.OverflowUint(uint64(source.Int())) {
+				return false
+			}
+			target.SetUint(uint64(source.Int()))
+			return true
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		f = float64(source.Uint())
+		switch target.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if source.Uint() > math.MaxInt64 || target.OverflowInt(int64(source.Uint())) {
+				return false
+			}
+			target.SetInt(int64(source.Uint()))
+			return true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if target.OverflowUint(source.Uint()) {
+				return false
+			}
+			target.SetUint(source.Uint())
+			return true
+		}
+	case reflect.Float32, reflect.Float64:
+		f = source.Float()
+	default:
+		return false
+	}
+
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 || target.OverflowInt(int64(f)) {
+			return false
+		}
+		target.SetInt(int64(f))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 || target.OverflowUint(uint64(f)) {
+			return false
+		}
+		target.SetUint(uint64(f))
+	case reflect.Float32, reflect.Float64:
+		if target.OverflowFloat(f) {
+			return false
+		}
+		target.SetFloat(f)
+	default:
+		return false
+	}
+	return true
+}
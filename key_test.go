@@ -0,0 +1,75 @@
+package eerror
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+type testKeyAddress struct {
+	City string
+	Zip  int
+}
+
+// TestKey ensures typed keys set and read attributes of the key type
+func TestKey(t *testing.T) {
+	userID := Key[int64]("user_id")
+	eerr := NewError(E_TESTERROR, "user {user_id} not found")
+	userID.Set(&eerr, 42)
+
+	if value, ok := userID.Get(eerr); !ok || value != 42 {
+		t.Error("Typed keys should read the attributes they set\n", value, ok)
+	}
+	if value, ok := Key[string]("user_id").Get(eerr); ok {
+		t.Error("Typed keys shouldn't read attributes of another type\n", value)
+	}
+	if value, ok := Key[int64]("missing").Get(eerr); ok || value != 0 {
+		t.Error("Typed keys shouldn't read missing attributes\n", value)
+	}
+	if _, ok := userID.Get(nil); ok {
+		t.Error("Typed keys shouldn't read nil errors\n")
+	}
+
+	eerr.WithAttribute("card_number", NewSecret("4111 1111 1111 1111"))
+	if value, ok := Key[string]("card_number").Get(eerr); ok {
+		t.Error("Typed keys shouldn't coerce secret values\n", value)
+	}
+	if value, ok := Key[Secret[string]]("card_number").Get(eerr); !ok || value.Reveal() != "4111 1111 1111 1111" {
+		t.Error("Typed keys should read secret values of their own type\n", value, ok)
+	}
+}
+
+// TestKeyCoercion ensures typed keys coerce parsed and decoded values to the key type
+func TestKeyCoercion(t *testing.T) {
+	parsed := From(errors.New("E_SOMEERROR: message [user_id: (int64)42, ratio: (float64)0.5, delay: (time.Duration)1.5s, admin: (bool)true]"))
+	if value, ok := Key[int64]("user_id").Get(parsed); !ok || value != 42 {
+		t.Error("Typed keys should coerce parsed values\n", value, ok, parsed.GetAttributes()["user_id"])
+	}
+	if value, ok := Key[float32]("ratio").Get(parsed); !ok || value != 0.5 {
+		t.Error("Typed keys should coerce parsed floats\n", value, ok)
+	}
+	if value, ok := Key[time.Duration]("delay").Get(parsed); !ok || value != 1500*time.Millisecond {
+		t.Error("Typed keys should coerce parsed durations\n", value, ok)
+	}
+	if value, ok := Key[bool]("admin").Get(parsed); !ok || !value {
+		t.Error("Typed keys should coerce parsed booleans\n", value, ok)
+	}
+
+	var decoded map[string]interface{}
+	json.Unmarshal([]byte(`{"user_id": 42, "half": 0.5, "large": 300, "address": {"City": "Paris", "Zip": 75001}}`), &decoded)
+	eerr := NewError(E_TESTERROR, "decoded error", "user_id", decoded["user_id"], "half", decoded["half"], "large", decoded["large"], "address", decoded["address"])
+
+	if value, ok := Key[int64]("user_id").Get(eerr); !ok || value != 42 {
+		t.Error("Typed keys should coerce decoded numbers\n", value, ok)
+	}
+	if value, ok := Key[int]("half").Get(eerr); ok {
+		t.Error("Typed keys shouldn't truncate decoded numbers\n", value)
+	}
+	if value, ok := Key[uint8]("large").Get(eerr); ok {
+		t.Error("Typed keys shouldn't overflow decoded numbers\n", value)
+	}
+	if value, ok := Key[testKeyAddress]("address").Get(eerr); !ok || value != (testKeyAddress{"Paris", 75001}) {
+		t.Error("Typed keys should coerce decoded objects\n", value, ok)
+	}
+}
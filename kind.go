@@ -0,0 +1,62 @@
+package eerror
+
+import "strings"
+
+/*
+DeclareKind declares the parent kind of an identifier, for identifiers whose kind isn't already described by their dotted form.
+Dotted identifiers are implicitly children of their prefix: E_DB.CONSTRAINT.UNIQUE is of kind E_DB.CONSTRAINT, itself of kind E_DB.
+
+  registry.DeclareKind("E_DEADLOCK", "E_DB")
+*/
+func (r *Registry) DeclareKind(identifier, parent string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.parents == nil {
+		r.parents = make(map[string]string)
+	}
+	r.parents[identifier] = parent
+}
+
+// Parent returns the parent kind of an identifier, as declared or described by its dotted form, or an empty string
+func (r *Registry) Parent(identifier string) string {
+	r.mutex.RLock()
+	parent, ok := r.parents[identifier]
+	r.mutex.RUnlock()
+
+	if ok {
+		return parent
+	}
+	if index := strings.LastIndexByte(identifier, '.'); index != -1 {
+		return identifier[:index]
+	}
+	return ""
+}
+
+// IsKind tests whether an identifier is the given kind, or one of its descendants
+func (r *Registry) IsKind(identifier, kind string) bool {
+	visited := make(map[string]bool)
+	for ; identifier != "" && !visited[identifier]; identifier = r.Parent(identifier) {
+		if identifier == kind {
+			return true
+		}
+		visited[identifier] = true
+	}
+	return false
+}
+
+// DeclareKind declares the parent kind of an identifier in the default registry
+func DeclareKind(identifier, parent string) {
+	DefaultRegistry.DeclareKind(identifier, parent)
+}
+
+/*
+IsKind tests whether the error identifier is the given kind, or one of its descendants, as declared in the default registry.
+
+  if eerr.IsKind("E_DB") {
+     return retry(operation)
+  }
+*/
+func (e Eerror) IsKind(kind string) bool {
+	return DefaultRegistry.IsKind(e.identifier, kind)
+}
@@ -0,0 +1,32 @@
+package eerror
+
+import "testing"
+
+// TestKinds ensures identifiers are matched by kind, from their dotted form or declared parents
+func TestKinds(t *testing.T) {
+	registry := NewRegistry()
+	registry.DeclareKind("E_DEADLOCK", "E_DB.TRANSACTION")
+	registry.DeclareKind("E_CYCLE_A", "E_CYCLE_B")
+	registry.DeclareKind("E_CYCLE_B", "E_CYCLE_A")
+
+	for _, test := range []struct {
+		identifier, kind string
+		expected         bool
+	}{
+		{"E_DB.CONSTRAINT.UNIQUE", "E_DB", true},
+		{"E_DB.CONSTRAINT.UNIQUE", "E_DB.CONSTRAINT", true},
+		{"E_DB.CONSTRAINT.UNIQUE", "E_DB.CONSTRAINT.UNIQUE", true},
+		{"E_DB.CONSTRAINT", "E_DB.CONSTRAINT.UNIQUE", false},
+		{"E_DBX.TIMEOUT", "E_DB", false},
+		{"E_DEADLOCK", "E_DB", true},
+		{"E_CYCLE_A", "E_DB", false},
+	} {
+		if registry.IsKind(test.identifier, test.kind) != test.expected {
+			t.Error("Invalid kind matching (identifier, kind, expected)\n", test.identifier, test.kind, test.expected)
+		}
+	}
+
+	if eerr := NewError("E_DB.TIMEOUT", "database timeout"); !eerr.IsKind("E_DB") {
+		t.Error("Enhanced error should match its kind")
+	}
+}
@@ -0,0 +1,57 @@
+package eerror
+
+import (
+	"fmt"
+	"sync"
+)
+
+/*
+Lazy describes an attribute value computed only when the error is formatted: by Error, Map, Render, slog, ...
+Attribute values of type func() interface{} are lazy as well, being called each time the error is formatted.
+
+  eerr.WithAttribute("body", func() interface{} {
+     return dumpBody(request)
+  })
+*/
+type Lazy interface {
+	Resolve() interface{}
+}
+
+// memoized is a lazy value computed once
+type memoized struct {
+	once  sync.Once
+	fn    func() interface{}
+	value interface{}
+}
+
+// Memoize returns a lazy value computed once, on first formatting, then shared by every copy of the error
+func Memoize(fn func() interface{}) Lazy {
+	return &memoized{fn: fn}
+}
+
+func (m *memoized) Resolve() interface{} {
+	m.once.Do(func() {
+		m.value = resolve(m.fn)
+	})
+	return m.value
+}
+
+// resolve computes a lazy value, the panics of its computation being replaced by a placeholder. Other values are returned as is
+func resolve(value interface{}) (resolved interface{}) {
+	var fn func() interface{}
+	switch lazy := value.(type) {
+	case Lazy:
+		fn = lazy.Resolve
+	case func() interface{}:
+		fn = lazy
+	default:
+		return value
+	}
+
+	defer (func() {
+		if recovered := recover(); recovered != nil {
+			resolved = fmt.Sprintf("[PANIC: %v]", recovered)
+		}
+	})()
+	return fn()
+}
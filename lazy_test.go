@@ -0,0 +1,76 @@
+package eerror
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLazyAttribute ensures lazy attributes are only computed when formatted, memoized if asked
+func TestLazyAttribute(t *testing.T) {
+	calls, memoizedCalls := 0, 0
+	eerr := NewError(E_TESTERROR, "request failed with {body}",
+		"body", func() interface{} {
+			calls++
+			return "request body"
+		},
+		"summary", Memoize(func() interface{} {
+			memoizedCalls++
+			return 42
+		}),
+	)
+	if calls != 0 || memoizedCalls != 0 {
+		t.Error("Lazy attributes shouldn't be computed before formatting\n", calls, memoizedCalls)
+	}
+
+	if message := eerr.Message(); message != "request failed with request body" {
+		t.Error("Lazy attributes should be interpolated once computed\n", message)
+	}
+	if !strings.Contains(eerr.Error(), "summary: (int)42") {
+		t.Error("Lazy attributes should be formatted once computed\n", eerr.Error())
+	}
+	if attributes := eerr.Map()["attributes"].(map[string]interface{}); attributes["body"] != "request body" || attributes["summary"] != 42 {
+		t.Error("Lazy attributes should be mapped once computed\n", attributes)
+	}
+	if calls != 3 || memoizedCalls != 1 {
+		t.Error("Lazy attributes should be computed on each formatting, unless memoized\n", calls, memoizedCalls)
+	}
+}
+
+// TestLazyAttributeWrap ensures wrapping only computes the lazy attributes rendered in the context
+func TestLazyAttributeWrap(t *testing.T) {
+	calls := 0
+	eerr := Wrap(NewError(E_TESTERROR, "request failed"), "calling {service}",
+		"service", func() interface{} {
+			return "billing"
+		},
+		"body", func() interface{} {
+			calls++
+			return "request body"
+		},
+	)
+	if calls != 0 {
+		t.Error("Lazy attributes missing from the context shouldn't be computed when wrapping\n", calls)
+	}
+	if contexts := eerr.contexts; len(contexts) != 1 || contexts[0] != "calling billing" {
+		t.Error("Lazy attributes of the context should be interpolated once computed\n", contexts)
+	}
+}
+
+// TestLazyAttributePanic ensures panics computing lazy attributes don't crash formatting
+func TestLazyAttributePanic(t *testing.T) {
+	eerr := NewError(E_TESTERROR, "test error",
+		"panicking", func() interface{} {
+			panic("dump failed")
+		},
+		"memoized", Memoize(func() interface{} {
+			panic("summary failed")
+		}),
+	)
+
+	if attributes := eerr.Map()["attributes"].(map[string]interface{}); attributes["panicking"] != "[PANIC: dump failed]" || attributes["memoized"] != "[PANIC: summary failed]" {
+		t.Error("Panics computing lazy attributes should be formatted as placeholders\n", attributes)
+	}
+	if !strings.Contains(eerr.Error(), "memoized: \"[PANIC: summary failed]\"") {
+		t.Error("Memoized panics should be formatted as placeholders\n", eerr.Error())
+	}
+}
@@ -0,0 +1,337 @@
+package eerror
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const E_INVALIDCATALOG = "E_INVALIDCATALOG"
+
+/*
+Catalog holds localized messages of enhanced errors, by locale and identifier.
+Localized messages are templates too, rendered from the error attributes.
+Catalogs are loaded from JSON files, mapping locales to identifiers and their messages.
+Plural messages are described by an object holding the plural forms, and the attribute to count from ("count" by default).
+
+  {
+     "fr": {
+        "E_USER_NOT_FOUND": "utilisateur {user_id} introuvable",
+        "E_ITEMS_MISSING": {"count": "items", "one": "{items} élément manquant", "other": "{items} éléments manquants"}
+     }
+  }
+*/
+type Catalog struct {
+	mutex    sync.RWMutex
+	messages map[string]map[string]catalogMessage
+}
+
+type catalogMessage struct {
+	count string
+	forms map[string]string
+}
+
+// DefaultCatalog is the catalog used by Localize and MessageIn
+var DefaultCatalog = NewCatalog()
+
+// NewCatalog instanciates an empty message catalog
+func NewCatalog() *Catalog {
+	return &Catalog{
+		messages: make(map[string]map[string]catalogMessage),
+	}
+}
+
+// LoadFile loads localized messages from a JSON file, merging them with the already loaded ones
+func (c *Catalog) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		eerr := From(err)
+		eerr.InContext("loading catalog " + path)
+		return eerr
+	}
+	defer file.Close()
+
+	if err := c.Load(file); err != nil {
+		eerr := From(err)
+		eerr.WithAttribute("path", path)
+		return eerr
+	}
+	return nil
+}
+
+// Load loads localized messages from a JSON stream, merging them with the already loaded ones
+func (c *Catalog) Load(r io.Reader) error {
+	var locales map[string]map[string]catalogMessage
+
+	if err := json.NewDecoder(r).Decode(&locales); err != nil {
+		return NewError(E_INVALIDCATALOG, "Invalid message catalog", "reason", err.Error())
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.messages == nil {
+		c.messages = make(map[string]map[string]catalogMessage, len(locales))
+	}
+	for locale, messages := range locales {
+		locale = normalizeLocale(locale)
+		if c.messages[locale] == nil {
+			c.messages[locale] = make(map[string]catalogMessage, len(messages))
+		}
+		for identifier, message := range messages {
+			c.messages[locale][identifier] = message
+		}
+	}
+	return nil
+}
+
+// Set defines the localized message of an identifier
+func (c *Catalog) Set(locale, identifier, message string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	locale = normalizeLocale(locale)
+	if c.messages == nil {
+		c.messages = make(map[string]map[string]catalogMessage)
+	}
+	if c.messages[locale] == nil {
+		c.messages[locale] = make(map[string]catalogMessage)
+	}
+	c.messages[locale][identifier] = catalogMessage{forms: map[string]string{"other": message}}
+}
+
+// Locales returns the sorted list of locales known by the catalog
+func (c *Catalog) Locales() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	locales := make([]string, 0, len(c.messages))
+	for locale := range c.messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+/*
+Localize renders the error message in the given locale.
+Falls back from a regional locale ("fr-CA") to its language ("fr"), then to the error message itself.
+*/
+func (c *Catalog) Localize(err Eerror, locale string) string {
+	if message, ok := c.localize(err, locale, err.visibleAttributes(AudienceInternal)); ok {
+		return message
+	}
+	return err.Message()
+}
+
+// localize renders the error message in the given locale from the given attributes, if the catalog holds it
+func (c *Catalog) localize(err Eerror, locale string, attributes map[string]interface{}) (string, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for locale = normalizeLocale(locale); locale != ""; locale = parentLocale(locale) {
+		message, ok := c.messages[locale][err.identifier]
+		if !ok {
+			continue
+		}
+
+		form := "other"
+		if count, ok := countAttribute(resolve(err.attributes[message.countAttribute()])); ok {
+			form = pluralForm(locale, count)
+		}
+		if template, ok := message.forms[form]; ok {
+			return interpolate(template, attributes), true
+		}
+		if template, ok := message.forms["other"]; ok {
+			return interpolate(template, attributes), true
+		}
+	}
+	return "", false
+}
+
+/*
+Negotiate picks the catalog locale best matching an Accept-Language header value, or an empty string if none matches.
+
+  locale := eerror.DefaultCatalog.Negotiate(request.Header.Get("Accept-Language"))
+*/
+func (c *Catalog) Negotiate(acceptLanguage string) string {
+	type preference struct {
+		locale  string
+		quality float64
+	}
+
+	var preferences []preference
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		locale := normalizeLocale(fields[0])
+		if locale == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			if value := strings.TrimSpace(param); strings.HasPrefix(value, "q=") {
+				if q, err := strconv.ParseFloat(value[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality > 0 {
+			preferences = append(preferences, preference{locale, quality})
+		}
+	}
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].quality > preferences[j].quality
+	})
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	for _, preference := range preferences {
+		for locale := preference.locale; locale != ""; locale = parentLocale(locale) {
+			if _, ok := c.messages[locale]; ok {
+				return locale
+			}
+		}
+	}
+	return ""
+}
+
+// Localize renders the message of any error in the given locale, from the default catalog
+func Localize(err interface{}, locale string) string {
+	return DefaultCatalog.Localize(From(err), locale)
+}
+
+// MessageIn renders the error message in the given language, from the default catalog
+func (e Eerror) MessageIn(lang string) string {
+	return DefaultCatalog.Localize(e, lang)
+}
+
+func (m *catalogMessage) UnmarshalJSON(data []byte) error {
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		m.forms = map[string]string{"other": message}
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &m.forms); err != nil {
+		return err
+	}
+	m.count = m.forms["count"]
+	delete(m.forms, "count")
+	return nil
+}
+
+func (m catalogMessage) countAttribute() string {
+	if m.count == "" {
+		return "count"
+	}
+	return m.count
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.Replace(strings.TrimSpace(locale), "_", "-", -1))
+}
+
+func parentLocale(locale string) string {
+	if index := strings.LastIndexByte(locale, '-'); index != -1 {
+		return locale[:index]
+	}
+	return ""
+}
+
+func countAttribute(value interface{}) (int64, bool) {
+	switch count := value.(type) {
+	case int:
+		return int64(count), true
+	case int8:
+		return int64(count), true
+	case int16:
+		return int64(count), true
+	case int32:
+		return int64(count), true
+	case int64:
+		return count, true
+	case uint:
+		return int64(count), true
+	case uint8:
+		return int64(count), true
+	case uint16:
+		return int64(count), true
+	case uint32:
+		return int64(count), true
+	case uint64:
+		return int64(count), true
+	case float32:
+		return int64(count), true
+	case float64:
+		return int64(count), true
+	case string:
+		n, err := strconv.ParseInt(count, 10, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// pluralForm returns the CLDR plural category of an integer count, for the most common languages
+func pluralForm(locale string, n int64) string {
+	if n < 0 {
+		n = -n
+	}
+	language := locale
+	if index := strings.IndexByte(locale, '-'); index != -1 {
+		language = locale[:index]
+	}
+
+	switch language {
+	case "ja", "zh", "ko", "vi", "th", "id", "ms":
+		return "other"
+	case "fr", "pt":
+		if n < 2 {
+			return "one"
+		}
+	case "ru", "uk", "be", "sr", "hr", "bs":
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		}
+		return "many"
+	case "pl":
+		switch {
+		case n == 1:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		}
+		return "many"
+	case "cs", "sk":
+		switch {
+		case n == 1:
+			return "one"
+		case n >= 2 && n <= 4:
+			return "few"
+		}
+	case "ar":
+		switch {
+		case n == 0:
+			return "zero"
+		case n == 1:
+			return "one"
+		case n == 2:
+			return "two"
+		case n%100 >= 3 && n%100 <= 10:
+			return "few"
+		case n%100 >= 11:
+			return "many"
+		}
+	default:
+		if n == 1 {
+			return "one"
+		}
+	}
+	return "other"
+}
@@ -0,0 +1,87 @@
+package eerror
+
+import (
+	"strings"
+	"testing"
+)
+
+const E_TESTERROR_LOCALIZED = "E_TESTERROR_LOCALIZED"
+
+// TestLocalize ensures messages are localized from catalogs, with plural forms and fallbacks
+func TestLocalize(t *testing.T) {
+	catalog := NewCatalog()
+	err := catalog.Load(strings.NewReader(`{
+		"fr": {
+			"E_TESTERROR": "utilisateur {user_id} introuvable",
+			"E_TESTERROR_LOCALIZED": {"count": "items", "one": "{items} élément manquant", "other": "{items} éléments manquants"}
+		},
+		"ru": {
+			"E_TESTERROR_LOCALIZED": {"count": "items", "one": "{items} элемент", "few": "{items} элемента", "many": "{items} элементов"}
+		}
+	}`))
+	if err != nil {
+		t.Fatal("Valid catalog should load\n", err)
+	}
+
+	eerr := NewError(E_TESTERROR, "user {user_id} not found", "user_id", 42)
+	if message := catalog.Localize(eerr, "fr-CA"); message != "utilisateur 42 introuvable" {
+		t.Error("Regional locales should fall back to their language\n", message)
+	}
+	if message := catalog.Localize(eerr, "de"); message != "user 42 not found" {
+		t.Error("Unknown locales should fall back to the error message\n", message)
+	}
+
+	for locale, expectations := range map[string]map[int]string{
+		"fr": {0: "0 élément manquant", 1: "1 élément manquant", 2: "2 éléments manquants"},
+		"ru": {1: "1 элемент", 3: "3 элемента", 11: "11 элементов", 21: "21 элемент"},
+	} {
+		for count, expect := range expectations {
+			eerr := NewError(E_TESTERROR_LOCALIZED, "{items} items missing", "items", count)
+			if message := catalog.Localize(eerr, locale); message != expect {
+				t.Error("Invalid plural form (locale, count, result, expected)\n", locale, count, message, expect)
+			}
+		}
+	}
+
+	if err := catalog.Load(strings.NewReader(`{"fr": 42}`)); err == nil || From(err).Id() != E_INVALIDCATALOG {
+		t.Error("Invalid catalog shouldn't load\n", err)
+	}
+}
+
+// TestCatalogZeroValue ensures messages can be set and loaded on a zero-value catalog
+func TestCatalogZeroValue(t *testing.T) {
+	eerr := NewError(E_TESTERROR, "user {user_id} not found", "user_id", 42)
+
+	catalog := &Catalog{}
+	catalog.Set("fr", E_TESTERROR, "utilisateur {user_id} introuvable")
+	if message := catalog.Localize(eerr, "fr"); message != "utilisateur 42 introuvable" {
+		t.Error("Zero-value catalog should hold set messages\n", message)
+	}
+
+	catalog = &Catalog{}
+	if err := catalog.Load(strings.NewReader(`{"de": {"E_TESTERROR": "Benutzer {user_id} nicht gefunden"}}`)); err != nil {
+		t.Fatal("Valid catalog should load\n", err)
+	}
+	if message := catalog.Localize(eerr, "de"); message != "Benutzer 42 nicht gefunden" {
+		t.Error("Zero-value catalog should hold loaded messages\n", message)
+	}
+}
+
+// TestNegotiate ensures the catalog locale best matching an Accept-Language header is picked
+func TestNegotiate(t *testing.T) {
+	catalog := NewCatalog()
+	catalog.Set("fr", E_TESTERROR, "erreur")
+	catalog.Set("en-GB", E_TESTERROR, "error")
+
+	for header, expect := range map[string]string{
+		"fr-CH, fr;q=0.9, en;q=0.8": "fr",
+		"de, en-GB;q=0.5, fr;q=0.4": "en-gb",
+		"en-GB;q=0, fr;q=0.1":       "fr",
+		"de":                        "",
+		"":                          "",
+	} {
+		if locale := catalog.Negotiate(header); locale != expect {
+			t.Error("Invalid negotiated locale (header, result, expected)\n", header, locale, expect)
+		}
+	}
+}
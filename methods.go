@@ -2,7 +2,10 @@ package eerror
 
 import (
 	"fmt"
+	"runtime"
 	"runtime/debug"
+	"strings"
+	"time"
 )
 
 /*
@@ -31,26 +34,93 @@ func NewError(identifier, message string, attributeKeyValPairs ...interface{}) E
 		message,
 		[]string{},
 		make(map[string]interface{}, len(attributeKeyValPairs)/2),
+		nil,
+		0,
+		0,
+		"",
+		nil,
+		nil,
+		nil,
+		time.Now(),
 		generateUniqueID(),
 	}
 
-	e.WithAttribute("stacktrace", string(debug.Stack()))
+	e.withInternalAttributes("stacktrace", string(debug.Stack()))
 	e.WithAttributes(attributeKeyValPairs...)
 	return e
 }
 
 // InContext appends a new context to the error stack. Useful to describe context during error forwarding.
 func (e *Eerror) InContext(context string) {
-	e.contexts = append(e.contexts, context)
+	// Capping the slice forces append to reallocate, so copies sharing the previous contexts are left untouched
+	e.contexts = append(e.contexts[:len(e.contexts):len(e.contexts)], context)
+}
+
+/*
+InCallerContext appends the calling function name as a new context to the error stack.
+Useful when forwarding an error, as the context would most of the time only repeat the enclosing function name.
+
+  func loadConfig(path string) error {
+     if err := readFile(path); err != nil {
+        eerr := eerror.From(err)
+        eerr.InCallerContext() // Appends "main.loadConfig"
+        return eerr
+     }
+     return nil
+  }
+*/
+func (e *Eerror) InCallerContext() {
+	e.InContext(callerName(2))
 }
 
-// WithAttribute allows attribute set to an error. If any attribute with the same name exists, it will be reset
+/*
+Annotate appends a formatted context to the error pointed by errp, converting it as an enhanced error.
+Does nothing if the pointed error is nil, allowing its use as a deferred call with named return values.
+
+  func loadConfig(path string) (err error) {
+     defer eerror.Annotate(&err, "loading config %s", path)
+
+     return readFile(path)
+  }
+*/
+func Annotate(errp *error, format string, args ...interface{}) {
+	if errp == nil || *errp == nil {
+		return
+	}
+
+	eerr := From(*errp)
+	eerr.InContext(fmt.Sprintf(format, args...))
+	*errp = eerr
+}
+
+/*
+Derive returns a copy of the error, in the given context and with the given attributes, leaving the error itself untouched.
+Safe to call concurrently on a shared error. An empty context appends no context.
+*/
+func (e Eerror) Derive(context string, attributeKeyValPairs ...interface{}) Eerror {
+	if context != "" {
+		e.InContext(context)
+	}
+	if len(attributeKeyValPairs) > 0 {
+		e.WithAttributes(attributeKeyValPairs...)
+	}
+	return e
+}
+
+// WithAttribute allows attribute set to an error. If any attribute with the same name exists, it is handled according to its conflict policy
 func (e *Eerror) WithAttribute(name string, value interface{}) {
 	e.WithAttributes(name, value)
 }
 
-// WithAttributes allow setting multiple attributes at once. If any attribute with the same name exists, they will be reset
+/*
+WithAttributes allow setting multiple attributes at once. If any attribute with the same name exists, it is handled according to its conflict policy:
+reset by default, see SetConflictPolicy.
+Attributes are copied on write: other copies of the error, as returned by From, keep their own attributes.
+Values are stored by reference, unless captured according to their snapshot policy (see SetSnapshot).
+*/
 func (e *Eerror) WithAttributes(attributeKeyValPairs ...interface{}) {
+	e.attributes = copyAttributes(e.attributes, len(attributeKeyValPairs)/2)
+
 	for i, value := range attributeKeyValPairs {
 		if i%2 != 0 {
 			continue
@@ -65,19 +135,61 @@ func (e *Eerror) WithAttributes(attributeKeyValPairs ...interface{}) {
 		if len(attributeKeyValPairs) > i+1 {
 			value = attributeKeyValPairs[i+1]
 		}
-		if e.attributes == nil {
-			e.attributes = make(map[string]interface{})
+		e.setAttribute(key, DefaultRegistry.snapshot(key, value))
+	}
+}
+
+// withInternalAttributes sets the attributes written by the package itself, bypassing their snapshot and conflict policies
+func (e *Eerror) withInternalAttributes(attributeKeyValPairs ...interface{}) {
+	e.attributes = copyAttributes(e.attributes, len(attributeKeyValPairs)/2)
+
+	for i := 0; i+1 < len(attributeKeyValPairs); i += 2 {
+		key := attributeKeyValPairs[i].(string)
+		if _, exists := e.attributes[key]; !exists {
+			e.order = append(e.order[:len(e.order):len(e.order)], key)
 		}
-		e.attributes[key] = value
+		e.attributes[key] = attributeKeyValPairs[i+1]
 	}
 }
 
-// GetAttributes retrieves the attributes map copy
+// GetAttributes retrieves the attributes map copy, lazy values being left unresolved
 func (e Eerror) GetAttributes() map[string]interface{} {
-	return e.attributes
+	return copyAttributes(e.attributes, 0)
 }
 
 // Id returns the identifier of the error
 func (e Eerror) Id() string {
 	return e.identifier
 }
+
+// Created returns the time the error was created at. Copies and forwarded errors keep the time of the original error
+func (e Eerror) Created() time.Time {
+	return e.created
+}
+
+// callerName returns the qualified function name of the caller at the given depth, trimmed from its package path
+func callerName(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip)
+	if !ok {
+		return "unknown"
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return "unknown"
+	}
+
+	name := fn.Name()
+	if index := strings.LastIndexByte(name, '/'); index != -1 {
+		name = name[index+1:]
+	}
+	return name
+}
+
+// copyAttributes returns a new attributes map holding the given ones, with room for extra attributes
+func copyAttributes(attributes map[string]interface{}, extra int) map[string]interface{} {
+	copied := make(map[string]interface{}, len(attributes)+extra)
+	for key, value := range attributes {
+		copied[key] = value
+	}
+	return copied
+}
@@ -5,6 +5,7 @@ import (
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // parse unserializes an enhanced error from it's string representation to the Eerror format
@@ -38,6 +39,10 @@ func parse(err interface{}) (eerr Eerror, ok bool) {
 		return
 	}
 	s = s[endPos:]
+	if len(strings.TrimSpace(s)) > 0 {
+		ok = false
+		return
+	}
 
 	eerr = Eerror{
 		err,
@@ -46,11 +51,19 @@ func parse(err interface{}) (eerr Eerror, ok bool) {
 		message,
 		contexts,
 		attributes,
-
+		nil,
+		0,
+		0,
+		"",
+		nil,
+		nil,
+		nil,
+
+		time.Now(),
 		generateUniqueID(),
 	}
 	if _, ok := attributes["stacktrace"]; !ok {
-		eerr.WithAttribute("stacktrace", string(debug.Stack()))
+		eerr.withInternalAttributes("stacktrace", string(debug.Stack()))
 	}
 	return
 }
@@ -95,7 +108,8 @@ func parseEerrorAttributes(s string) (attributes map[string]interface{}, ok bool
 			return
 		}
 		index = endPosition + 2
-		if s[index] == '"' {
+		quoted := s[index] == '"'
+		if quoted {
 			endPosition = strings.IndexByte(s[index+1:], '"')
 			if endPosition == -1 {
 				return
@@ -113,8 +127,9 @@ func parseEerrorAttributes(s string) (attributes map[string]interface{}, ok bool
 			value = s[index:endPosition]
 		}
 
-		if indexLastPar := strings.IndexByte(value.(string)[1:], ')'); value.(string)[0] == '(' && indexLastPar != -1 && indexLastPar < len(value.(string))-1 {
-			t := value.(string)[1 : indexLastPar-1]
+		// Quoted values are strings, whatever their content
+		if indexLastPar := strings.IndexByte(value.(string), ')'); !quoted && len(value.(string)) > 0 && value.(string)[0] == '(' && indexLastPar != -1 && indexLastPar < len(value.(string))-1 {
+			t := value.(string)[1:indexLastPar]
 
 			switch t {
 			case "string":
@@ -165,6 +180,7 @@ func parseEerrorAttributes(s string) (attributes map[string]interface{}, ok bool
 	}
 
 	ok = true
+	endPosition = index + 1
 	return
 }
 
@@ -174,6 +190,11 @@ func parseEerrorContexts(s string) (contexts []string, ok bool, endPosition int)
 		return
 	}
 
+	if s[0] == '[' {
+		// No contexts, attributes follow the message
+		return
+	}
+
 	ok = false
 	if s[0:1] != "(" {
 		return
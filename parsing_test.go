@@ -51,6 +51,9 @@ func TestSerialization(t *testing.T) {
 			"E_SOMEERROR: message (context) [attribute: (int)-1]",
 			"E_SOMEERROR: message (context) [attribute: \"(int)string value\"]",
 			"E_SOMEERROR: \"some long; and (very) [complex message]\" (context)",
+			"E_SOMEERROR: user {user_id} not found [user_id: 42]",
+			"E_SOMEERROR: user {user_id} not found (context)",
+			"E_DB.CONSTRAINT.UNIQUE: duplicated entry (context)",
 		} {
 			if eerr, ok = parse(test); !ok {
 				return
@@ -102,6 +105,20 @@ func TestSerialization(t *testing.T) {
 		t.Error("Parsing test should've failed, but succeeded (test, built error)", failedTest+"\n", failedError)
 	}
 
+	templated := From("E_SOMEERROR: user {user_id} lacks {permission} [user_id: 42, permission: write]")
+	if templated.MessageTemplate() != "user {user_id} lacks {permission}" || templated.Message() != "user 42 lacks write" {
+		t.Error("Parsed message templates should be rendered from parsed attributes (template, message)\n", templated.MessageTemplate()+"\n", templated.Message())
+	}
+
+	if dotted := From("E_DB.CONSTRAINT.UNIQUE: duplicated entry"); dotted.Id() != "E_DB.CONSTRAINT.UNIQUE" || !dotted.IsKind("E_DB") {
+		t.Error("Dotted identifiers should be parsed, keeping their kind\n", dotted.Id())
+	}
+
+	typed := From("E_SOMEERROR: message [count: (int)-1, quoted: \"(int)string value\"]").GetAttributes()
+	if typed["count"] != -1 || typed["quoted"] != "(int)string value" {
+		t.Error("Typed attributes should be parsed, quoted ones being kept as strings\n", typed["count"], typed["quoted"])
+	}
+
 	eerr := From(err.Error())
 	if eerr.Error() != err.Error() {
 		t.Error("Bad parsing, both should be equals (result, expected)\n", err.Error()+"\n", eerr.Error())
@@ -0,0 +1,100 @@
+package eerror
+
+import (
+	"context"
+	"runtime/debug"
+	"runtime/pprof"
+)
+
+const E_PANIC = "E_PANIC"
+
+/*
+Recover converts a panic into an E_PANIC enhanced error, stored into the error pointed by errp.
+Must be deferred directly. The panic value is converted through From, and kept as the "panic" attribute,
+along with the stack trace of the panicking goroutine. An enhanced error panic value is kept as the cause of the E_PANIC error instead.
+The goroutine profiler labels are only reachable through a context, hence only attached by RecoverContext.
+If errp is nil, the panic goes on with the E_PANIC error.
+
+  func handle() (err error) {
+     defer eerror.Recover(&err)
+
+     return process()
+  }
+*/
+func Recover(errp *error) {
+	if r := recover(); r != nil {
+		recovered(errp, fromPanic(r, nil))
+	}
+}
+
+// RecoverContext acts as Recover, additionally attaching the goroutine profiler labels from the given context
+func RecoverContext(ctx context.Context, errp *error) {
+	if r := recover(); r != nil {
+		recovered(errp, fromPanic(r, ctx))
+	}
+}
+
+/*
+Go runs the given function in a new goroutine, converting its panic into an E_PANIC enhanced error rather than crashing the process.
+The returned channel receives the function result once done.
+As Recover, Go attaches no profiler labels: the function may defer RecoverContext itself to attach them.
+
+  if err := <-eerror.Go(worker); err != nil {
+     log.Println(err)
+  }
+*/
+func Go(fn func() error) <-chan error {
+	result := make(chan error, 1)
+
+	go (func() {
+		var err error
+		defer (func() {
+			result <- err
+		})()
+		defer Recover(&err)
+
+		err = fn()
+	})()
+	return result
+}
+
+// recovered stores the error converted from a panic into the error pointed by errp, panicking with it if errp is nil
+func recovered(errp *error, eerr Eerror) {
+	if errp == nil {
+		panic(eerr)
+	}
+	*errp = eerr
+}
+
+func fromPanic(r interface{}, ctx context.Context) Eerror {
+	// Called from the deferred function, hence still on top of the panicking goroutine stack
+	stack := string(debug.Stack())
+
+	var eerr Eerror
+	switch r.(type) {
+	case Eerror, *Eerror:
+		// Enhanced errors keep their own identifier, as the cause of the panic
+		cause := From(r)
+		eerr = NewError(E_PANIC, "panicked with "+cause.identifier)
+		eerr.WithCauses(cause)
+	default:
+		eerr = From(r)
+		eerr.identifier = E_PANIC
+	}
+	eerr.withInternalAttributes(
+		"panic", r,
+		"stacktrace", stack,
+	)
+
+	if ctx != nil {
+		labels := make(map[string]string)
+		pprof.ForLabels(ctx, func(key, value string) bool {
+			labels[key] = value
+			return true
+		})
+		if len(labels) > 0 {
+			eerr.withInternalAttributes("labels", labels)
+		}
+	}
+	return eerr
+}
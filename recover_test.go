@@ -0,0 +1,89 @@
+package eerror
+
+import (
+	"context"
+	"fmt"
+	"runtime/pprof"
+	"strings"
+	"testing"
+)
+
+func panickingFunction(value interface{}) error {
+	panic(value)
+}
+
+// TestRecover ensures panics are converted into enhanced errors, with the panicking goroutine stack
+func TestRecover(t *testing.T) {
+	stdError := fmt.Errorf("standard error")
+
+	err := (func() (err error) {
+		defer Recover(&err)
+		return panickingFunction(stdError)
+	})()
+
+	eerr, ok := err.(Eerror)
+	if !ok || eerr.Id() != E_PANIC {
+		t.Fatal("Recovered panic should be converted into an E_PANIC enhanced error\n", err)
+	}
+	if eerr.GetAttributes()["panic"] != stdError || !eerr.Is(stdError) {
+		t.Error("Recovered panic should hold and be related to the panic value\n", eerr.GetAttributes()["panic"])
+	}
+	if stack := eerr.GetAttributes()["stacktrace"].(string); !strings.Contains(stack, "panickingFunction") {
+		t.Error("Recovered panic should hold the panicking goroutine stack\n", stack)
+	}
+
+	if err := (func() (err error) {
+		defer Recover(&err)
+		return nil
+	})(); err != nil {
+		t.Error("Recover shouldn't set an error without panic\n", err)
+	}
+
+	eerrPanic := NewError(E_TESTERROR, "enhanced error panic")
+	err = (func() (err error) {
+		defer Recover(&err)
+		return panickingFunction(eerrPanic)
+	})()
+	if eerr := From(err); eerr.Id() != E_PANIC || len(eerr.Causes()) != 1 || eerr.Causes()[0].Id() != E_TESTERROR || !eerr.Is(eerrPanic) {
+		t.Error("Recovered enhanced error panic should be kept as the cause of the E_PANIC error\n", err)
+	}
+	if eerrPanic.Id() != E_TESTERROR {
+		t.Error("Recovered enhanced error panic shouldn't be updated\n", eerrPanic)
+	}
+
+	defer (func() {
+		if recovered, ok := recover().(Eerror); !ok || recovered.Id() != E_PANIC {
+			t.Error("Recover without error pointer should go on panicking with the E_PANIC error\n", recovered)
+		}
+	})()
+	(func() {
+		defer Recover(nil)
+		panickingFunction("panic message")
+	})()
+}
+
+// TestRecoverContext ensures the profiler labels of the panicking goroutine are attached
+func TestRecoverContext(t *testing.T) {
+	var err error
+	pprof.Do(context.Background(), pprof.Labels("worker", "test"), func(ctx context.Context) {
+		err = (func() (err error) {
+			defer RecoverContext(ctx, &err)
+			return panickingFunction("panic message")
+		})()
+	})
+
+	labels, ok := From(err).GetAttributes()["labels"].(map[string]string)
+	if !ok || labels["worker"] != "test" {
+		t.Error("Recovered panic should hold the goroutine labels\n", err)
+	}
+}
+
+// TestGo ensures panicking goroutines don't crash the process
+func TestGo(t *testing.T) {
+	if err := <-Go(func() error { return panickingFunction("panic message") }); From(err).Id() != E_PANIC {
+		t.Error("Panicking goroutine should result in an E_PANIC enhanced error\n", err)
+	}
+	if err := <-Go(func() error { return nil }); err != nil {
+		t.Error("Goroutine shouldn't result in an error\n", err)
+	}
+}
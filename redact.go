@@ -0,0 +1,255 @@
+package eerror
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"regexp"
+)
+
+// RedactionPolicy describes how a redacted attribute value is replaced
+type RedactionPolicy int
+
+const (
+	// RedactMask replaces the value by Redacted
+	RedactMask RedactionPolicy = iota
+	// RedactHash replaces the value by its keyed hash, so that occurrences of a same value may still be correlated
+	RedactHash
+	// RedactDrop removes the attribute
+	RedactDrop
+)
+
+/*
+RedactionRule describes attribute values to be redacted from every output of an error: Error, Map, JSON, slog, Render, ...
+A rule matching by key redacts whole values, while a rule matching by value only redacts the matching parts of string values,
+including the strings held by maps, slices and arrays (such as headers), but not by structures nor pointers.
+Both may be combined, to only redact the matching parts of the values of matching keys.
+
+  eerror.AddRedaction(eerror.RedactionRule{
+     Name:   "emails",
+     Value:  regexp.MustCompile(`[\w.+-]+@[\w-]+(\.[\w-]+)+`),
+     Policy: eerror.RedactHash,
+  })
+*/
+type RedactionRule struct {
+	Name string
+	// Key matches the names of the attributes to redact
+	Key *regexp.Regexp
+	// Value matches the parts of string attribute values to redact. A dropping rule drops the whole attribute
+	Value  *regexp.Regexp
+	Policy RedactionPolicy
+}
+
+// credentialsRule masks the attributes named after credentials, registered by default into each registry
+var credentialsRule = RedactionRule{
+	Name:   "credentials",
+	Key:    regexp.MustCompile(`(?i)passw(or)?d|secret|token|api[-_]?key|authorization|cookie|credential`),
+	Policy: RedactMask,
+}
+
+/*
+Secret wraps an attribute value never to be formatted, whatever the output: it is replaced according to the secret policy.
+The value remains accessible through Reveal.
+
+  eerr.WithAttribute("card_number", eerror.NewSecret(cardNumber))
+*/
+type Secret[T any] struct {
+	value T
+}
+
+// secretValue is implemented by Secret values only
+type secretValue interface {
+	reveal() interface{}
+}
+
+// NewSecret wraps a secret attribute value
+func NewSecret[T any](value T) Secret[T] {
+	return Secret[T]{value}
+}
+
+// Reveal returns the wrapped secret value
+func (s Secret[T]) Reveal() T {
+	return s.value
+}
+
+func (s Secret[T]) reveal() interface{} {
+	return s.value
+}
+
+// String formats the secret as Redacted
+func (s Secret[T]) String() string {
+	return Redacted
+}
+
+// Format formats the secret as Redacted, whatever the verb
+func (s Secret[T]) Format(f fmt.State, verb rune) {
+	io.WriteString(f, Redacted)
+}
+
+// MarshalJSON marshals the secret as Redacted
+func (s Secret[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Redacted)
+}
+
+// LogValue logs the secret as Redacted
+func (s Secret[T]) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
+
+// AddRedaction appends a redaction rule, applied to the attributes of every error after the previous rules
+func (r *Registry) AddRedaction(rule RedactionRule) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.redactions = append(r.redactions, rule)
+}
+
+// SetSecretPolicy sets how Secret values and VisibilitySecret attributes are replaced, RedactMask by default
+func (r *Registry) SetSecretPolicy(policy RedactionPolicy) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.secretPolicy = policy
+}
+
+/*
+SetRedactionKey sets the key of the hashes replacing values redacted with RedactHash.
+Without key, low-entropy values (emails, card numbers, ...) are easily recovered from their hash: set a key kept out of the logs.
+*/
+func (r *Registry) SetRedactionKey(key []byte) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.redactionKey = append([]byte{}, key...)
+}
+
+// AddRedaction appends a redaction rule to the default registry
+func AddRedaction(rule RedactionRule) {
+	DefaultRegistry.AddRedaction(rule)
+}
+
+// SetSecretPolicy sets how Secret values and VisibilitySecret attributes are replaced, in the default registry
+func SetSecretPolicy(policy RedactionPolicy) {
+	DefaultRegistry.SetSecretPolicy(policy)
+}
+
+// SetRedactionKey sets the key of the hashes replacing values redacted with RedactHash, in the default registry
+func SetRedactionKey(key []byte) {
+	DefaultRegistry.SetRedactionKey(key)
+}
+
+// redact returns an attribute value as it may be formatted, or false if it is dropped
+func (r *Registry) redact(key string, value interface{}, secret bool) (interface{}, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if wrapped, ok := value.(secretValue); ok {
+		return r.replace(wrapped.reveal(), r.secretPolicy)
+	}
+	if secret {
+		return r.replace(value, r.secretPolicy)
+	}
+
+	for _, rule := range r.redactions {
+		if rule.Key != nil && !rule.Key.MatchString(key) {
+			continue
+		}
+		if rule.Value == nil {
+			return r.replace(value, rule.Policy)
+		}
+
+		redacted, matched := r.redactStrings(reflect.ValueOf(value), rule)
+		if !matched {
+			continue
+		}
+		if rule.Policy == RedactDrop {
+			return nil, false
+		}
+		value = redacted.Interface()
+	}
+	return value, true
+}
+
+// redactStrings redacts the parts of strings matched by a value rule, recursing into maps, slices and arrays.
+// Values holding matching strings are copied, never updated in place
+func (r *Registry) redactStrings(value reflect.Value, rule RedactionRule) (reflect.Value, bool) {
+	switch value.Kind() {
+	case reflect.String:
+		if !rule.Value.MatchString(value.String()) {
+			return value, false
+		}
+		if rule.Policy == RedactDrop {
+			// The whole attribute is dropped
+			return value, true
+		}
+		redacted := reflect.New(value.Type()).Elem()
+		redacted.SetString(rule.Value.ReplaceAllStringFunc(value.String(), func(match string) string {
+			replaced, _ := r.replace(match, rule.Policy)
+			return replaced.(string)
+		}))
+		return redacted, true
+	case reflect.Interface:
+		if value.IsNil() {
+			return value, false
+		}
+		elem, matched := r.redactStrings(value.Elem(), rule)
+		if !matched {
+			return value, false
+		}
+		redacted := reflect.New(value.Type()).Elem()
+		redacted.Set(elem)
+		return redacted, true
+	case reflect.Map:
+		var redacted reflect.Value
+		for entries := value.MapRange(); entries.Next(); {
+			elem, matched := r.redactStrings(entries.Value(), rule)
+			if !matched {
+				continue
+			}
+			if !redacted.IsValid() {
+				redacted = reflect.MakeMapWithSize(value.Type(), value.Len())
+				for copied := value.MapRange(); copied.Next(); {
+					redacted.SetMapIndex(copied.Key(), copied.Value())
+				}
+			}
+			redacted.SetMapIndex(entries.Key(), elem)
+		}
+		return redacted, redacted.IsValid()
+	case reflect.Slice, reflect.Array:
+		var redacted reflect.Value
+		for i := 0; i < value.Len(); i++ {
+			elem, matched := r.redactStrings(value.Index(i), rule)
+			if !matched {
+				continue
+			}
+			if !redacted.IsValid() {
+				if value.Kind() == reflect.Slice {
+					redacted = reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+				} else {
+					redacted = reflect.New(value.Type()).Elem()
+				}
+				reflect.Copy(redacted, value)
+			}
+			redacted.Index(i).Set(elem)
+		}
+		return redacted, redacted.IsValid()
+	}
+	return value, false
+}
+
+func (r *Registry) replace(value interface{}, policy RedactionPolicy) (interface{}, bool) {
+	switch policy {
+	case RedactDrop:
+		return nil, false
+	case RedactHash:
+		mac := hmac.New(sha256.New, r.redactionKey)
+		fmt.Fprint(mac, value)
+		return "[sha256:" + hex.EncodeToString(mac.Sum(nil))[:16] + "]", true
+	}
+	return Redacted, true
+}
@@ -0,0 +1,70 @@
+package eerror
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestRedaction ensures attributes are redacted by key, value and type, according to the rule policies
+func TestRedaction(t *testing.T) {
+	registry := DefaultRegistry
+	registry.mutex.RLock()
+	redactions := registry.redactions
+	registry.mutex.RUnlock()
+	t.Cleanup(func() {
+		registry.mutex.Lock()
+		defer registry.mutex.Unlock()
+
+		registry.redactions = redactions
+	})
+
+	registry.AddRedaction(RedactionRule{Name: "test emails", Key: regexp.MustCompile(`^test_contact$`), Value: regexp.MustCompile(`[\w.+-]+@[\w-]+(\.[\w-]+)+`), Policy: RedactHash})
+	registry.AddRedaction(RedactionRule{Name: "test header emails", Key: regexp.MustCompile(`^headers$`), Value: regexp.MustCompile(`[\w.+-]+@[\w-]+(\.[\w-]+)+`), Policy: RedactMask})
+	registry.AddRedaction(RedactionRule{Name: "test cards", Value: regexp.MustCompile(`\btestcard-\d+\b`), Policy: RedactDrop})
+
+	eerr := NewError(E_TESTERROR, "cannot notify {test_contact}",
+		"test_contact", "alice <alice@example.com>",
+		"api_key", "k3y",
+		"note", "paid with testcard-4242",
+		"pin", NewSecret(918273645),
+		"headers", map[string][]string{"X-Contact": {"bob@example.com"}, "Accept": {"text/plain"}},
+		"payments", []interface{}{"testcard-1111"},
+	)
+
+	attributes := eerr.Map()["attributes"].(map[string]interface{})
+	if contact := attributes["test_contact"].(string); !strings.HasPrefix(contact, "alice <[sha256:") || strings.Contains(contact, "alice@example.com") {
+		t.Error("Matching parts of values should be hashed\n", contact)
+	}
+	if attributes["api_key"] != Redacted || attributes["pin"] != Redacted {
+		t.Error("Credentials and secret values should be masked\n", attributes)
+	}
+	if _, ok := attributes["note"]; ok {
+		t.Error("Dropped attributes shouldn't be formatted\n", attributes)
+	}
+	if headers := attributes["headers"].(map[string][]string); headers["X-Contact"][0] != Redacted || headers["Accept"][0] != "text/plain" {
+		t.Error("Matching parts of nested values should be redacted\n", headers)
+	}
+	if _, ok := attributes["payments"]; ok {
+		t.Error("Attributes holding dropped nested values shouldn't be formatted\n", attributes)
+	}
+	if headers := eerr.GetAttributes()["headers"].(map[string][]string); headers["X-Contact"][0] != "bob@example.com" {
+		t.Error("Nested values shouldn't be redacted in place\n", headers)
+	}
+	if other := NewError(E_TESTERROR, "other", "test_contact", "alice@example.com").Map()["attributes"].(map[string]interface{}); other["test_contact"] != attributes["test_contact"].(string)[len("alice <"):len(attributes["test_contact"].(string))-1] {
+		t.Error("Hashes of a same value should be equal\n", other, attributes)
+	}
+
+	marshaled, _ := json.Marshal(eerr)
+	for _, output := range []string{eerr.Error(), eerr.Message(), string(marshaled), fmt.Sprintf("%#v", eerr), fmt.Sprint(eerr.GetAttributes()["pin"])} {
+		if strings.Contains(output, "alice@example.com") || strings.Contains(output, "k3y") || strings.Contains(output, "testcard-4242") || strings.Contains(output, "918273645") || strings.Contains(output, "bob@example.com") || strings.Contains(output, "testcard-1111") {
+			t.Error("Redacted values shouldn't be formatted\n", output)
+		}
+	}
+
+	if pin := eerr.GetAttributes()["pin"].(Secret[int]); pin.Reveal() != 918273645 {
+		t.Error("Secret values should remain accessible\n", pin.Reveal())
+	}
+}
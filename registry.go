@@ -0,0 +1,112 @@
+package eerror
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+/*
+Registry holds the error templates declared by an application, by identifier, their kinds and classifications, and the translators used to convert errors.
+Templates declared through Define, and translators registered through RegisterTranslator, are registered into the DefaultRegistry.
+Libraries may instanciate their own registry, not to share global state.
+*/
+type Registry struct {
+	mutex       sync.RWMutex
+	templates   map[string]*Template
+	translators []Translator
+	parents     map[string]string
+	retryable   map[string]bool
+	severities  map[string]Severity
+	classes     map[string]Class
+	visibility  map[string]Visibility
+
+	redactions   []RedactionRule
+	secretPolicy RedactionPolicy
+	redactionKey []byte
+
+	defaultSnapshot SnapshotPolicy
+	snapshots       map[string]SnapshotPolicy
+
+	order           AttributeOrder
+	defaultConflict ConflictPolicy
+	conflicts       map[string]ConflictPolicy
+}
+
+// DefaultRegistry is the registry used by Define and Lookup
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry instanciates an empty registry, masking the attributes named after credentials (password, token, ...)
+func NewRegistry() *Registry {
+	return &Registry{
+		templates:  make(map[string]*Template),
+		severities: map[string]Severity{E_PANIC: SeverityCritical},
+		classes:    map[string]Class{E_PANIC: ClassBug},
+		visibility: make(map[string]Visibility),
+		redactions: []RedactionRule{credentialsRule},
+		snapshots:  make(map[string]SnapshotPolicy),
+		conflicts:  make(map[string]ConflictPolicy),
+	}
+}
+
+// Define declares a new error template into the registry. Panics if the identifier is already declared
+func (r *Registry) Define(identifier, message string) *Template {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.templates[identifier]; ok {
+		panic(fmt.Sprintf("eerror: identifier %s declared twice", identifier))
+	}
+
+	t := &Template{
+		identifier: identifier,
+		message:    message,
+	}
+	if r.templates == nil {
+		r.templates = make(map[string]*Template)
+	}
+	r.templates[identifier] = t
+	return t
+}
+
+// Lookup retrieves the template declared with the given identifier
+func (r *Registry) Lookup(identifier string) (*Template, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	t, ok := r.templates[identifier]
+	return t, ok
+}
+
+// Identifiers returns the sorted list of identifiers declared in the registry
+func (r *Registry) Identifiers() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	identifiers := make([]string, 0, len(r.templates))
+	for identifier := range r.templates {
+		identifiers = append(identifiers, identifier)
+	}
+	sort.Strings(identifiers)
+	return identifiers
+}
+
+// lookupKind walks an identifier and its kinds, until found returns true
+func (r *Registry) lookupKind(identifier string, found func(identifier string) bool) {
+	visited := make(map[string]bool)
+	for ; identifier != "" && !visited[identifier]; identifier = r.Parent(identifier) {
+		r.mutex.RLock()
+		ok := found(identifier)
+		r.mutex.RUnlock()
+
+		if ok {
+			return
+		}
+		visited[identifier] = true
+	}
+}
+
+// Lookup retrieves the template declared with the given identifier in the default registry
+func Lookup(identifier string) (*Template, bool) {
+	return DefaultRegistry.Lookup(identifier)
+}
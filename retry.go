@@ -0,0 +1,181 @@
+package eerror
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+/*
+SetRetryable classifies an identifier, and its descendant identifiers, as retryable or not.
+The "retryable" attribute of an error overrides the classification of its identifier.
+
+  registry.SetRetryable("E_DB.TIMEOUT", true)
+*/
+func (r *Registry) SetRetryable(identifier string, retryable bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.retryable == nil {
+		r.retryable = make(map[string]bool)
+	}
+	r.retryable[identifier] = retryable
+}
+
+// IsRetryable tests whether an error is retryable, from its "retryable" attribute, or the classification of its identifier
+func (r *Registry) IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	eerr := r.From(err)
+	if retryable, ok := resolve(eerr.attributes["retryable"]).(bool); ok {
+		return retryable
+	}
+
+	retryable := false
+	r.lookupKind(eerr.identifier, func(identifier string) bool {
+		classified, ok := r.retryable[identifier]
+		retryable = classified
+		return ok
+	})
+	return retryable
+}
+
+// SetRetryable classifies an identifier, and its descendant identifiers, as retryable or not in the default registry
+func SetRetryable(identifier string, retryable bool) {
+	DefaultRegistry.SetRetryable(identifier, retryable)
+}
+
+// IsRetryable tests whether an error is retryable, as classified in the default registry
+func IsRetryable(err error) bool {
+	return DefaultRegistry.IsRetryable(err)
+}
+
+/*
+RetryAfter returns the delay to wait before retrying, from the "retry_after" attribute of an error.
+The attribute is either a time.Duration, a duration string ("1.5s"), or a number of seconds.
+*/
+func RetryAfter(err error) (time.Duration, bool) {
+	if err == nil {
+		return 0, false
+	}
+	return retryAfter(From(err))
+}
+
+// retryAfter returns the delay to wait before retrying, from the "retry_after" attribute of an already converted error
+func retryAfter(eerr Eerror) (time.Duration, bool) {
+	switch value := resolve(eerr.attributes["retry_after"]).(type) {
+	case time.Duration:
+		return value, true
+	case string:
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration, true
+		}
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+			return time.Duration(seconds * float64(time.Second)), true
+		}
+	case int:
+		return time.Duration(value) * time.Second, true
+	case float64:
+		return time.Duration(value * float64(time.Second)), true
+	}
+	return 0, false
+}
+
+// RetryPolicy describes how Retry retries a failing function. Zero values stand for the defaults
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of calls, 3 by default
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry, 100ms by default
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries, 10s by default
+	MaxBackoff time.Duration
+	// Multiplier increases the delay after each retry, 2 by default
+	Multiplier float64
+	// Jitter randomizes each delay by up to the given fraction of it, none by default
+	Jitter float64
+	// Registry classifies errors as retryable, the default registry by default
+	Registry *Registry
+}
+
+/*
+Retry calls fn until it succeeds, retrying only retryable errors, with exponential backoff.
+A "retry_after" attribute on the error overrides the backoff delay.
+The final error is put in a context counting the attempts, and holds every previous failure as its causes.
+
+  err := eerror.Retry(ctx, eerror.RetryPolicy{MaxAttempts: 5}, func(ctx context.Context) error {
+     return client.Call(ctx, request)
+  })
+*/
+func Retry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error) error {
+	policy = policy.withDefaults()
+
+	var failures []error
+	backoff := policy.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if attempt < policy.MaxAttempts && policy.Registry.IsRetryable(err) {
+			delay := policy.delay(backoff)
+			if retryAfter, ok := retryAfter(policy.Registry.From(err)); ok {
+				delay = retryAfter
+			}
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+				failures = append(failures, err)
+				// Capped before converting back, not to overflow after many retries
+				if next := float64(backoff) * policy.Multiplier; next < float64(policy.MaxBackoff) {
+					backoff = time.Duration(next)
+				} else {
+					backoff = policy.MaxBackoff
+				}
+				continue
+			case <-ctx.Done():
+				timer.Stop()
+			}
+		}
+
+		eerr := policy.Registry.From(err)
+		eerr.InContext(fmt.Sprintf("attempt %d/%d", attempt, policy.MaxAttempts))
+		eerr.withInternalAttributes("attempts", attempt)
+		eerr.WithCauses(failures...)
+		return eerr
+	}
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 100 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 10 * time.Second
+	}
+	if p.Multiplier <= 0 {
+		p.Multiplier = 2
+	}
+	if p.Registry == nil {
+		p.Registry = DefaultRegistry
+	}
+	return p
+}
+
+func (p RetryPolicy) delay(backoff time.Duration) time.Duration {
+	if backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		backoff += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(backoff))
+	}
+	return backoff
+}
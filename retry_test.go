@@ -0,0 +1,109 @@
+package eerror
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestRetryable ensures errors are classified as retryable from their identifier kind, or their attributes
+func TestRetryable(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetRetryable("E_DB", true)
+	registry.SetRetryable("E_DB.CONSTRAINT", false)
+
+	for _, test := range []struct {
+		err      Eerror
+		expected bool
+	}{
+		{NewError("E_DB.TIMEOUT", "database timeout"), true},
+		{NewError("E_DB.CONSTRAINT.UNIQUE", "duplicated entry"), false},
+		{NewError("E_DB.CONSTRAINT.UNIQUE", "duplicated entry", "retryable", true), true},
+		{NewError(E_TESTERROR, "test error"), false},
+	} {
+		if registry.IsRetryable(test.err) != test.expected {
+			t.Error("Invalid retryable classification (identifier, expected)\n", test.err.Id(), test.expected)
+		}
+	}
+
+	if delay, ok := RetryAfter(NewError(E_TESTERROR, "test error", "retry_after", "1.5")); !ok || delay != 1500*time.Millisecond {
+		t.Error("Retry-after delay should be read from the attributes\n", delay)
+	}
+}
+
+// TestRetry ensures only retryable errors are retried, the final error holding the previous failures
+func TestRetry(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetRetryable(E_TESTERROR, true)
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, Jitter: 0.5, Registry: registry}
+
+	attempts := 0
+	err := Retry(context.Background(), policy, func(ctx context.Context) error {
+		attempts++
+		return NewError(E_TESTERROR, "retryable error", "attempt", attempts)
+	})
+
+	eerr := From(err)
+	if attempts != 3 || eerr.GetAttributes()["attempts"] != 3 || eerr.contexts[len(eerr.contexts)-1] != "attempt 3/3" {
+		t.Error("Retryable errors should be retried until the maximum attempts\n", attempts, eerr)
+	}
+	if causes := eerr.Causes(); len(causes) != 2 || causes[0].GetAttributes()["attempt"] != 1 {
+		t.Error("Final error should hold the previous failures\n", causes)
+	}
+
+	attempts = 0
+	err = Retry(context.Background(), policy, func(ctx context.Context) error {
+		attempts++
+		if attempts == 1 {
+			return NewError(E_TESTERROR, "retryable error", "retry_after", time.Millisecond)
+		}
+		return nil
+	})
+	if err != nil || attempts != 2 {
+		t.Error("Retry should succeed once the function succeeds\n", attempts, err)
+	}
+
+	attempts = 0
+	err = Retry(context.Background(), policy, func(ctx context.Context) error {
+		attempts++
+		return NewError(E_TESTERROR_WITH_ATTRIBUTES, "permanent error")
+	})
+	if attempts != 1 || From(err).GetAttributes()["attempts"] != 1 {
+		t.Error("Non retryable errors shouldn't be retried\n", attempts, err)
+	}
+}
+
+// TestRetryBackoff ensures the backoff stays capped over many retries, and retry-after delays are read through the policy registry
+func TestRetryBackoff(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetRetryable(E_TESTERROR, true)
+	policy := RetryPolicy{MaxAttempts: 8, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond, Multiplier: 1e6, Registry: registry}
+
+	start := time.Now()
+	Retry(context.Background(), policy, func(ctx context.Context) error {
+		return NewError(E_TESTERROR, "retryable error")
+	})
+	if elapsed := time.Since(start); elapsed < 13*time.Millisecond {
+		t.Error("Backoff should stay capped to the maximum backoff, rather than overflow\n", elapsed)
+	}
+
+	throttled := fmt.Errorf("throttled")
+	registry.RegisterTranslator(Translator{
+		Name:  "throttled",
+		Match: func(err error) bool { return err == throttled },
+		Translate: func(err error) Translation {
+			return Translation{Identifier: E_TESTERROR, Attributes: []interface{}{"retry_after", time.Millisecond}}
+		},
+	})
+	policy = RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Hour, Registry: registry}
+
+	attempts := 0
+	err := Retry(context.Background(), policy, func(ctx context.Context) error {
+		attempts++
+		return throttled
+	})
+	if attempts != 2 || From(err).Id() != E_TESTERROR {
+		t.Error("Retry-after delay should be read from errors translated by the policy registry\n", attempts, err)
+	}
+}
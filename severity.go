@@ -0,0 +1,207 @@
+package eerror
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Severity describes how important an error is
+type Severity int
+
+const (
+	severityUnset Severity = iota
+	SeverityDebug
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+// Class describes who is at fault for an error
+type Class int
+
+const (
+	ClassUnknown Class = iota
+	// ClassClient errors are caused by the client input
+	ClassClient
+	// ClassServer errors are caused by the server itself
+	ClassServer
+	// ClassDependency errors are caused by an external dependency of the server
+	ClassDependency
+	// ClassBug errors are caused by a programming error
+	ClassBug
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityDebug:
+		return "debug"
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	}
+	return "unset"
+}
+
+// Level returns the slog level matching the severity, critical errors being logged above the error level
+func (s Severity) Level() slog.Level {
+	switch s {
+	case SeverityDebug:
+		return slog.LevelDebug
+	case SeverityInfo:
+		return slog.LevelInfo
+	case SeverityWarning:
+		return slog.LevelWarn
+	case SeverityCritical:
+		return slog.LevelError + 4
+	}
+	return slog.LevelError
+}
+
+func (c Class) String() string {
+	switch c {
+	case ClassClient:
+		return "client"
+	case ClassServer:
+		return "server"
+	case ClassDependency:
+		return "dependency"
+	case ClassBug:
+		return "bug"
+	}
+	return "unknown"
+}
+
+// SetSeverity sets the default severity of an identifier, and its descendant identifiers
+func (r *Registry) SetSeverity(identifier string, severity Severity) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.severities == nil {
+		r.severities = make(map[string]Severity)
+	}
+	r.severities[identifier] = severity
+}
+
+// SetClass sets the default class of an identifier, and its descendant identifiers
+func (r *Registry) SetClass(identifier string, class Class) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.classes == nil {
+		r.classes = make(map[string]Class)
+	}
+	r.classes[identifier] = class
+}
+
+// Severity returns the default severity of an identifier, SeverityError if not set
+func (r *Registry) Severity(identifier string) Severity {
+	severity := SeverityError
+	r.lookupKind(identifier, func(identifier string) bool {
+		set, ok := r.severities[identifier]
+		if ok {
+			severity = set
+		}
+		return ok
+	})
+	return severity
+}
+
+// Class returns the default class of an identifier, ClassUnknown if not set
+func (r *Registry) Class(identifier string) Class {
+	class := ClassUnknown
+	r.lookupKind(identifier, func(identifier string) bool {
+		set, ok := r.classes[identifier]
+		if ok {
+			class = set
+		}
+		return ok
+	})
+	return class
+}
+
+// SetSeverity sets the default severity of an identifier, and its descendant identifiers, in the default registry
+func SetSeverity(identifier string, severity Severity) {
+	DefaultRegistry.SetSeverity(identifier, severity)
+}
+
+// SetClass sets the default class of an identifier, and its descendant identifiers, in the default registry
+func SetClass(identifier string, class Class) {
+	DefaultRegistry.SetClass(identifier, class)
+}
+
+// WithSeverity overrides the severity of the error
+func (e *Eerror) WithSeverity(severity Severity) {
+	e.severity = severity
+}
+
+// WithClass overrides the class of the error
+func (e *Eerror) WithClass(class Class) {
+	e.class = class
+}
+
+// Severity returns the severity of the error, as overridden or defaulted from its identifier in the default registry
+func (e Eerror) Severity() Severity {
+	if e.severity != severityUnset {
+		return e.severity
+	}
+	return DefaultRegistry.Severity(e.identifier)
+}
+
+// Class returns the class of the error, as overridden or defaulted from its identifier in the default registry
+func (e Eerror) Class() Class {
+	if e.class != ClassUnknown {
+		return e.class
+	}
+	return DefaultRegistry.Class(e.identifier)
+}
+
+// LogValue describes the error as a structured slog group, along with its severity and class
+func (e Eerror) LogValue() slog.Value {
+	visible := e.visibleAttributes(AudienceInternal)
+	attributes := make([]any, 0, len(visible))
+	for _, key := range e.attributeKeys(visible) {
+		attributes = append(attributes, slog.Any(key, visible[key]))
+	}
+
+	return slog.GroupValue(
+		slog.String("code", e.identifier),
+		slog.String("message", interpolate(e.message, visible)),
+		slog.String("template", e.message),
+		slog.Any("contexts", e.contexts),
+		slog.Group("attributes", attributes...),
+		slog.String("severity", e.Severity().String()),
+		slog.String("class", e.Class().String()),
+	)
+}
+
+/*
+SlogReporter reports errors to a slog logger, at the level matching their severity.
+
+  handler := eerror.RecoverHandler(mux, eerror.SlogReporter(slog.Default()))
+*/
+func SlogReporter(logger *slog.Logger) Reporter {
+	return func(ctx context.Context, err Eerror) {
+		logger.Log(ctx, err.Severity().Level(), err.Message(), slog.Any("error", err))
+	}
+}
+
+/*
+FilterReporter forwards to the reporter only the errors kept by the filter.
+
+  pager := eerror.FilterReporter(pagerReporter, func(err eerror.Eerror) bool {
+     return err.Severity() >= eerror.SeverityCritical && err.Class() != eerror.ClassClient
+  })
+*/
+func FilterReporter(reporter Reporter, keep func(err Eerror) bool) Reporter {
+	return func(ctx context.Context, err Eerror) {
+		if keep(err) {
+			reporter(ctx, err)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package eerror
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+// TestSeverity ensures severities and classes default from the identifier kind, and can be overridden
+func TestSeverity(t *testing.T) {
+	DefaultRegistry.SetSeverity("E_TESTSEVERITY", SeverityWarning)
+	DefaultRegistry.SetClass("E_TESTSEVERITY", ClassDependency)
+
+	eerr := NewError("E_TESTSEVERITY.TIMEOUT", "dependency timeout")
+	if eerr.Severity() != SeverityWarning || eerr.Class() != ClassDependency {
+		t.Error("Severity and class should default from the identifier kind\n", eerr.Severity(), eerr.Class())
+	}
+
+	eerr.WithSeverity(SeverityCritical)
+	eerr.WithClass(ClassServer)
+	if eerr.Severity() != SeverityCritical || eerr.Class() != ClassServer {
+		t.Error("Severity and class should be overridden\n", eerr.Severity(), eerr.Class())
+	}
+	if mapped := eerr.Map(); mapped["severity"] != "critical" || mapped["class"] != "server" {
+		t.Error("Map should render the severity and class\n", mapped)
+	}
+
+	if plain := NewError(E_TESTERROR, "test error"); plain.Severity() != SeverityError || plain.Class() != ClassUnknown {
+		t.Error("Severity and class should default to error and unknown\n", plain.Severity(), plain.Class())
+	}
+	if panicked := From(<-Go(func() error { panic("panic") })); panicked.Severity() != SeverityCritical || panicked.Class() != ClassBug {
+		t.Error("Panics should be critical bugs\n", panicked.Severity(), panicked.Class())
+	}
+}
+
+// TestSeverityZeroRegistry ensures severities and classes can be set on a zero-value registry
+func TestSeverityZeroRegistry(t *testing.T) {
+	registry := &Registry{}
+	registry.SetSeverity(E_TESTERROR, SeverityWarning)
+	registry.SetClass(E_TESTERROR, ClassDependency)
+
+	if registry.Severity(E_TESTERROR) != SeverityWarning || registry.Class(E_TESTERROR) != ClassDependency {
+		t.Error("Zero-value registry should hold severities and classes\n", registry.Severity(E_TESTERROR), registry.Class(E_TESTERROR))
+	}
+}
+
+// TestSlogReporter ensures errors are logged at the level matching their severity, filtered as asked
+func TestSlogReporter(t *testing.T) {
+	var output bytes.Buffer
+	reporter := FilterReporter(SlogReporter(slog.New(slog.NewJSONHandler(&output, nil))), func(err Eerror) bool {
+		return err.Severity() >= SeverityCritical
+	})
+
+	eerr := NewError(E_TESTERROR, "user {user} not found", "user", 42)
+	reporter(context.Background(), eerr)
+	if output.Len() > 0 {
+		t.Error("Filtered errors shouldn't be reported\n", output.String())
+	}
+
+	eerr.WithSeverity(SeverityCritical)
+	reporter(context.Background(), eerr)
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(output.Bytes(), &record); err != nil {
+		t.Fatal("Reported error should be logged\n", err)
+	}
+	logged := record["error"].(map[string]interface{})
+	if record["level"] != "ERROR+4" || record["msg"] != "user 42 not found" || logged["code"] != E_TESTERROR || logged["severity"] != "critical" {
+		t.Error("Invalid logged error\n", record)
+	}
+	if logged["attributes"].(map[string]interface{})["user"] != float64(42) {
+		t.Error("Logged error should hold its attributes\n", logged)
+	}
+}
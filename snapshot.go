@@ -0,0 +1,232 @@
+package eerror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// SnapshotMode describes how attribute values are captured
+type SnapshotMode int
+
+const (
+	// SnapshotNone stores attribute values by reference (default): pointers, slices and maps may change before the error is formatted
+	SnapshotNone SnapshotMode = iota
+	// SnapshotCopy deep copies attribute values, unexported struct fields excepted, as they are shallow copied
+	SnapshotCopy
+	// SnapshotSerialize stores attribute values as serialized then decoded through JSON, into their own type, or as raw JSON if they can't be decoded
+	SnapshotSerialize
+)
+
+/*
+SnapshotPolicy describes how attribute values are captured when set, so that the error reports their state at that time.
+Values exceeding the limits are replaced by a placeholder. Lazy values are never captured, as they are computed when formatted,
+nor are immutable values (booleans, numbers and strings), nor the attributes set by the package itself, such as "stacktrace".
+
+  eerror.SetSnapshot(eerror.SnapshotPolicy{Mode: eerror.SnapshotCopy, MaxSize: 1000}, "request", "cart")
+*/
+type SnapshotPolicy struct {
+	Mode SnapshotMode
+	// MaxSize limits the number of bytes of serialized values, or the number of elements (items, entries and fields) of copied values. Zero means unlimited
+	MaxSize int
+	// MaxDepth limits the nesting of copied values. Zero means unlimited
+	MaxDepth int
+}
+
+var errSnapshotTooLarge = errors.New("value exceeds the snapshot limits")
+
+// SetSnapshot sets the snapshot policy of the given attributes, overriding the default one
+func (r *Registry) SetSnapshot(policy SnapshotPolicy, keys ...string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.snapshots == nil {
+		r.snapshots = make(map[string]SnapshotPolicy)
+	}
+	for _, key := range keys {
+		r.snapshots[key] = policy
+	}
+}
+
+// SetDefaultSnapshot sets the snapshot policy of the attributes without their own policy
+func (r *Registry) SetDefaultSnapshot(policy SnapshotPolicy) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.defaultSnapshot = policy
+}
+
+// SetSnapshot sets the snapshot policy of the given attributes, in the default registry
+func SetSnapshot(policy SnapshotPolicy, keys ...string) {
+	DefaultRegistry.SetSnapshot(policy, keys...)
+}
+
+// SetDefaultSnapshot sets the snapshot policy of the attributes without their own policy, in the default registry
+func SetDefaultSnapshot(policy SnapshotPolicy) {
+	DefaultRegistry.SetDefaultSnapshot(policy)
+}
+
+// snapshot captures an attribute value according to its snapshot policy
+func (r *Registry) snapshot(key string, value interface{}) interface{} {
+	r.mutex.RLock()
+	policy, ok := r.snapshots[key]
+	if !ok {
+		policy = r.defaultSnapshot
+	}
+	r.mutex.RUnlock()
+
+	switch value.(type) {
+	case nil, Lazy, func() interface{}:
+		return value
+	}
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128, reflect.String:
+		return value
+	}
+
+	switch policy.Mode {
+	case SnapshotCopy:
+		s := snapshotter{policy: policy, copies: make(map[snapshotPointer]reflect.Value)}
+		copied, err := s.deepCopy(reflect.ValueOf(value), 0)
+		if err != nil {
+			return fmt.Sprintf("[SNAPSHOT FAILED: %v]", err)
+		}
+		return copied.Interface()
+	case SnapshotSerialize:
+		serialized, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprintf("[SNAPSHOT FAILED: %v]", err)
+		}
+		if policy.MaxSize > 0 && len(serialized) > policy.MaxSize {
+			return fmt.Sprintf("[SNAPSHOT FAILED: %v]", errSnapshotTooLarge)
+		}
+
+		decoded := reflect.New(reflect.TypeOf(value))
+		if err := json.Unmarshal(serialized, decoded.Interface()); err != nil {
+			return json.RawMessage(serialized)
+		}
+		return decoded.Elem().Interface()
+	}
+	return value
+}
+
+type snapshotPointer struct {
+	address   uintptr
+	valueType reflect.Type
+}
+
+// snapshotter deep copies a value, counting its elements
+type snapshotter struct {
+	policy SnapshotPolicy
+	size   int
+	copies map[snapshotPointer]reflect.Value
+}
+
+func (s *snapshotter) grow(elements int) error {
+	s.size += elements
+	if s.policy.MaxSize > 0 && s.size > s.policy.MaxSize {
+		return errSnapshotTooLarge
+	}
+	return nil
+}
+
+func (s *snapshotter) deepCopy(value reflect.Value, depth int) (reflect.Value, error) {
+	if s.policy.MaxDepth > 0 && depth > s.policy.MaxDepth {
+		return value, errSnapshotTooLarge
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return value, nil
+		}
+		pointer := snapshotPointer{value.Pointer(), value.Type()}
+		if copied, ok := s.copies[pointer]; ok {
+			return copied, nil
+		}
+
+		copied := reflect.New(value.Type().Elem())
+		s.copies[pointer] = copied
+		elem, err := s.deepCopy(value.Elem(), depth+1)
+		if err != nil {
+			return value, err
+		}
+		copied.Elem().Set(elem)
+		return copied, nil
+	case reflect.Interface:
+		if value.IsNil() {
+			return value, nil
+		}
+		elem, err := s.deepCopy(value.Elem(), depth+1)
+		if err != nil {
+			return value, err
+		}
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(elem)
+		return copied, nil
+	case reflect.Slice:
+		if value.IsNil() {
+			return value, nil
+		}
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		return copied, s.copyElements(value, copied, depth)
+	case reflect.Array:
+		copied := reflect.New(value.Type()).Elem()
+		return copied, s.copyElements(value, copied, depth)
+	case reflect.Map:
+		if value.IsNil() {
+			return value, nil
+		}
+		if err := s.grow(value.Len()); err != nil {
+			return value, err
+		}
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		for entries := value.MapRange(); entries.Next(); {
+			key, err := s.deepCopy(entries.Key(), depth+1)
+			if err != nil {
+				return value, err
+			}
+			elem, err := s.deepCopy(entries.Value(), depth+1)
+			if err != nil {
+				return value, err
+			}
+			copied.SetMapIndex(key, elem)
+		}
+		return copied, nil
+	case reflect.Struct:
+		if err := s.grow(value.NumField()); err != nil {
+			return value, err
+		}
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(value)
+		for i := 0; i < value.NumField(); i++ {
+			if !value.Type().Field(i).IsExported() {
+				continue
+			}
+			field, err := s.deepCopy(value.Field(i), depth+1)
+			if err != nil {
+				return value, err
+			}
+			copied.Field(i).Set(field)
+		}
+		return copied, nil
+	}
+	return value, nil
+}
+
+func (s *snapshotter) copyElements(value, copied reflect.Value, depth int) error {
+	if err := s.grow(value.Len()); err != nil {
+		return err
+	}
+	for i := 0; i < value.Len(); i++ {
+		elem, err := s.deepCopy(value.Index(i), depth+1)
+		if err != nil {
+			return err
+		}
+		copied.Index(i).Set(elem)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package eerror
+
+import (
+	"strings"
+	"testing"
+)
+
+type testSnapshotUser struct {
+	Name  string
+	Roles []string
+	Boss  *testSnapshotUser
+}
+
+// restoreSnapshots restores the snapshot policies of the default registry once the test is done
+func restoreSnapshots(t *testing.T) {
+	DefaultRegistry.mutex.Lock()
+	snapshots, defaultSnapshot := DefaultRegistry.snapshots, DefaultRegistry.defaultSnapshot
+	DefaultRegistry.snapshots = make(map[string]SnapshotPolicy, len(snapshots))
+	for key, policy := range snapshots {
+		DefaultRegistry.snapshots[key] = policy
+	}
+	DefaultRegistry.mutex.Unlock()
+
+	t.Cleanup(func() {
+		DefaultRegistry.mutex.Lock()
+		defer DefaultRegistry.mutex.Unlock()
+
+		DefaultRegistry.snapshots, DefaultRegistry.defaultSnapshot = snapshots, defaultSnapshot
+	})
+}
+
+// TestSnapshotCopy ensures attribute values are deep copied when set, for attributes with a copy snapshot policy
+func TestSnapshotCopy(t *testing.T) {
+	restoreSnapshots(t)
+	SetSnapshot(SnapshotPolicy{Mode: SnapshotCopy}, "test_snapshot_user", "test_snapshot_cart")
+	SetSnapshot(SnapshotPolicy{Mode: SnapshotCopy, MaxSize: 3}, "test_snapshot_limited")
+
+	user := &testSnapshotUser{Name: "alice", Roles: []string{"admin"}}
+	user.Boss = user
+	cart := map[string]int{"apple": 1}
+	eerr := NewError(E_TESTERROR, "checkout failed",
+		"test_snapshot_user", user,
+		"test_snapshot_cart", cart,
+		"test_snapshot_shared", cart,
+		"test_snapshot_limited", []int{1, 2, 3, 4},
+	)
+
+	user.Name, user.Roles[0] = "bob", "guest"
+	cart["apple"] = 2
+
+	attributes := eerr.GetAttributes()
+	if captured := attributes["test_snapshot_user"].(*testSnapshotUser); captured.Name != "alice" || captured.Roles[0] != "admin" || captured.Boss != captured {
+		t.Error("Attribute values should be deep copied when set\n", captured)
+	}
+	if attributes["test_snapshot_cart"].(map[string]int)["apple"] != 1 || attributes["test_snapshot_shared"].(map[string]int)["apple"] != 2 {
+		t.Error("Only attributes with a snapshot policy should be copied\n", attributes)
+	}
+	if limited := attributes["test_snapshot_limited"]; !strings.HasPrefix(limited.(string), "[SNAPSHOT FAILED") {
+		t.Error("Attribute values exceeding the snapshot limits should be replaced by a placeholder\n", limited)
+	}
+}
+
+// TestSnapshotSerialize ensures attribute values are serialized when set, with the default snapshot policy
+func TestSnapshotSerialize(t *testing.T) {
+	restoreSnapshots(t)
+	SetDefaultSnapshot(SnapshotPolicy{Mode: SnapshotSerialize, MaxSize: 64})
+
+	user := &testSnapshotUser{Name: "alice", Roles: []string{"admin"}}
+	eerr := NewError(E_TESTERROR, "checkout failed", "user", user, "count", 42, "large", []string{strings.Repeat("a", 64)})
+	user.Name = "bob"
+
+	attributes := eerr.GetAttributes()
+	if captured, ok := attributes["user"].(*testSnapshotUser); !ok || captured == user || captured.Name != "alice" || captured.Roles[0] != "admin" {
+		t.Error("Attribute values should be serialized when set, then decoded into their own type\n", attributes["user"])
+	}
+	if attributes["count"] != 42 || !strings.Contains(attributes["stacktrace"].(string), "goroutine") {
+		t.Error("Immutable values and internal attributes shouldn't be serialized\n", attributes["count"], attributes["stacktrace"])
+	}
+	if large := attributes["large"]; !strings.HasPrefix(large.(string), "[SNAPSHOT FAILED") {
+		t.Error("Attribute values exceeding the snapshot limits should be replaced by a placeholder\n", large)
+	}
+}
+
+// TestSnapshotZeroRegistry ensures snapshot policies can be set on a zero-value registry
+func TestSnapshotZeroRegistry(t *testing.T) {
+	registry := &Registry{}
+	registry.SetSnapshot(SnapshotPolicy{Mode: SnapshotCopy}, "cart")
+
+	if copied := registry.snapshot("cart", []int{1}).([]int); len(copied) != 1 || copied[0] != 1 {
+		t.Error("Zero-value registry should capture values by their snapshot policy\n", copied)
+	}
+}
@@ -0,0 +1,79 @@
+package eerror
+
+/*
+Template describes a declarable error kind, to be instanciated each time the error occurs.
+Unlike an enhanced error declared at package level, each instance owns its stack trace and instance identifier,
+while still being related to its template through Is.
+
+  const E_MY_ERROR_ID = "E_MY_ERROR_ID"
+
+  var ErrMyError = eerror.Define(E_MY_ERROR_ID, "Some error")
+
+  func errorFunction(myParameter interface{}) eerror.Eerror {
+     return ErrMyError.Instance("parameter", myParameter)
+  }
+
+  func main() {
+     if eerr := errorFunction("hello world"); eerr.Is(ErrMyError) {
+        panic(eerr)
+     }
+  }
+*/
+type Template struct {
+	identifier string
+	message    string
+	attributes []string
+
+	public       string
+	visibilities map[string]Visibility
+}
+
+// Define declares a new error template given its unique identifier and message, registered into the default registry
+func Define(identifier, message string) *Template {
+	return DefaultRegistry.Define(identifier, message)
+}
+
+// Requires declares the attributes expected on each instance of the template
+func (t *Template) Requires(attributes ...string) *Template {
+	t.attributes = append(t.attributes, attributes...)
+	return t
+}
+
+// Attributes returns the attributes required on each instance of the template
+func (t *Template) Attributes() []string {
+	return append([]string{}, t.attributes...)
+}
+
+// Public declares the message displayed to clients on each instance of the template, rendered from public attributes only
+func (t *Template) Public(message string) *Template {
+	t.public = message
+	return t
+}
+
+// WithVisibility declares the visibility of the given attributes on each instance of the template
+func (t *Template) WithVisibility(visibility Visibility, keys ...string) *Template {
+	t.visibilities = copyVisibilities(t.visibilities, len(keys))
+	for _, key := range keys {
+		t.visibilities[key] = visibility
+	}
+	return t
+}
+
+// Instance instanciates a new enhanced error from the template, with a fresh stack trace and creation time, and potential attributes
+func (t *Template) Instance(attributeKeyValPairs ...interface{}) Eerror {
+	e := NewError(t.identifier, t.message, attributeKeyValPairs...)
+	e.parent = t
+	e.public = t.public
+	e.visibilities = t.visibilities
+	return e
+}
+
+// Id returns the identifier of the template
+func (t *Template) Id() string {
+	return t.identifier
+}
+
+// Error formats the template as its instances would be, without contexts nor attributes
+func (t *Template) Error() string {
+	return Eerror{identifier: t.identifier, message: t.message}.Error()
+}
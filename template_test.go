@@ -0,0 +1,107 @@
+package eerror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestTemplate ensures template instances are related to their template, while owning their stack trace
+func TestTemplate(t *testing.T) {
+	registry := NewRegistry()
+	template := registry.Define(E_TESTERROR, "This is a template error")
+	otherTemplate := registry.Define(E_TESTERROR_WITH_ATTRIBUTES, "This is another template error")
+
+	before := time.Now()
+	first := template.Instance("attribute", 1)
+	time.Sleep(time.Millisecond)
+	second := template.Instance("attribute", 2)
+
+	if first.Id() != E_TESTERROR || first.message != "This is a template error" {
+		t.Error("Template instance should inherit the template identifier and message\n", first)
+	}
+	if first._instance == second._instance {
+		t.Error("Template instances should own their instance identifier")
+	}
+	if first.GetAttributes()["stacktrace"] == second.GetAttributes()["stacktrace"] {
+		t.Error("Template instances should own their stack trace")
+	}
+	if first.Created().Before(before) || !second.Created().After(first.Created()) {
+		t.Error("Template instances should own their creation time\n", first.Created(), second.Created())
+	}
+
+	if !first.Is(template) || !second.Is(template) {
+		t.Error("Template instances should be related to their template")
+	}
+	if !first.Is(second) {
+		t.Error("Template instances should be related to each other")
+	}
+	if first.Is(otherTemplate) {
+		t.Error("Template instances shouldn't be related to another template")
+	}
+
+	forwarded := From(first)
+	forwarded.InContext("forwarded")
+	if !forwarded.Is(template) {
+		t.Error("Forwarded template instances should still be related to their template")
+	}
+	if !forwarded.Created().Equal(first.Created()) || !first.Dup().Created().Equal(first.Created()) {
+		t.Error("Forwarded template instances should keep their creation time\n", forwarded.Created())
+	}
+}
+
+// TestTemplateSentinel ensures templates may replace sentinel errors, callers comparing against them still working
+func TestTemplateSentinel(t *testing.T) {
+	sentinel := NewRegistry().Define(E_TESTERROR, "user not found")
+	find := func() error {
+		return sentinel
+	}
+
+	err := find()
+	if err != sentinel {
+		t.Error("Returned templates should compare equal to their sentinel\n", err)
+	}
+	if !errors.Is(fmt.Errorf("loading user: %w", err), sentinel) {
+		t.Error("Wrapped templates should match their sentinel through errors.Is\n", err)
+	}
+	if err.Error() != E_TESTERROR+": user not found" {
+		t.Error("Templates should be formatted as enhanced errors\n", err.Error())
+	}
+}
+
+// TestRegistry ensures templates are registered by identifier, once
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	template := registry.Define(E_TESTERROR, "This is a template error").Requires("attribute")
+
+	if found, ok := registry.Lookup(E_TESTERROR); !ok || found != template {
+		t.Error("Defined template should be registered by its identifier")
+	}
+	if _, ok := registry.Lookup(E_TESTERROR_WITH_ATTRIBUTES); ok {
+		t.Error("Undefined identifier shouldn't be registered")
+	}
+	if identifiers := registry.Identifiers(); len(identifiers) != 1 || identifiers[0] != E_TESTERROR {
+		t.Error("Registry should list its identifiers\n", identifiers)
+	}
+	if attributes := template.Attributes(); len(attributes) != 1 || attributes[0] != "attribute" {
+		t.Error("Template should list its required attributes\n", attributes)
+	}
+
+	defer (func() {
+		if recover() == nil {
+			t.Error("Defining an identifier twice should panic")
+		}
+	})()
+	registry.Define(E_TESTERROR, "This is a duplicated template error")
+}
+
+// TestRegistryZeroValue ensures templates can be defined on a zero-value registry
+func TestRegistryZeroValue(t *testing.T) {
+	registry := &Registry{}
+	template := registry.Define(E_TESTERROR, "This is a template error")
+
+	if found, ok := registry.Lookup(E_TESTERROR); !ok || found != template {
+		t.Error("Zero-value registry should register defined templates")
+	}
+}
@@ -0,0 +1,235 @@
+package eerror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net"
+	"os/exec"
+	"runtime/debug"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	E_NOTEXIST         = "E_NOTEXIST"
+	E_ALREADYEXISTS    = "E_ALREADYEXISTS"
+	E_PERMISSIONDENIED = "E_PERMISSIONDENIED"
+	E_FILESYSTEM       = "E_FILESYSTEM"
+	E_CANCELED         = "E_CANCELED"
+	E_DEADLINEEXCEEDED = "E_DEADLINEEXCEEDED"
+	E_EOF              = "E_EOF"
+	E_UNEXPECTEDEOF    = "E_UNEXPECTEDEOF"
+	E_NETWORK          = "E_NETWORK"
+	E_SYSCALL          = "E_SYSCALL"
+	E_INVALIDNUMBER    = "E_INVALIDNUMBER"
+	E_INVALIDJSON      = "E_INVALIDJSON"
+	E_EXITSTATUS       = "E_EXITSTATUS"
+)
+
+/*
+Translation describes the enhanced error an error is converted to.
+The message defaults to the converted error message.
+*/
+type Translation struct {
+	Identifier string
+	Message    string
+	Attributes []interface{}
+}
+
+/*
+Translator converts the errors it matches when given to From.
+Translators are consulted by decreasing priority, then by registration order, before the standard library translators.
+
+  eerror.RegisterTranslator(eerror.Translator{
+     Name:  "pq unique violation",
+     Match: func(err error) bool {
+        var pqErr *pq.Error
+        return errors.As(err, &pqErr) && pqErr.Code == "23505"
+     },
+     Translate: func(err error) eerror.Translation {
+        return eerror.Translation{Identifier: E_DUPLICATE}
+     },
+  })
+*/
+type Translator struct {
+	Name      string
+	Priority  int
+	Match     func(err error) bool
+	Translate func(err error) Translation
+}
+
+/*
+TranslateType instanciates a translator matching errors of the given type, as errors.As does.
+
+  eerror.RegisterTranslator(eerror.TranslateType("mysql error", 0, func(err *mysql.MySQLError) eerror.Translation {
+     return eerror.Translation{Identifier: E_MYSQL, Attributes: []interface{}{"number", err.Number}}
+  }))
+*/
+func TranslateType[T error](name string, priority int, translate func(err T) Translation) Translator {
+	return Translator{
+		Name:     name,
+		Priority: priority,
+		Match:    asError[T],
+		Translate: func(err error) Translation {
+			var target T
+			errors.As(err, &target)
+			return translate(target)
+		},
+	}
+}
+
+// standardTranslators maps well-known standard library errors to identifiers, by priority order
+var standardTranslators = []Translator{
+	standardTranslator("context.Canceled", E_CANCELED, isError(context.Canceled)),
+	standardTranslator("context.DeadlineExceeded", E_DEADLINEEXCEEDED, isError(context.DeadlineExceeded)),
+	standardTranslator("fs.ErrNotExist", E_NOTEXIST, isError(fs.ErrNotExist)),
+	standardTranslator("fs.ErrExist", E_ALREADYEXISTS, isError(fs.ErrExist)),
+	standardTranslator("fs.ErrPermission", E_PERMISSIONDENIED, isError(fs.ErrPermission)),
+	standardTranslator("io.ErrUnexpectedEOF", E_UNEXPECTEDEOF, isError(io.ErrUnexpectedEOF)),
+	standardTranslator("io.EOF", E_EOF, isError(io.EOF)),
+	standardTranslator("*strconv.NumError", E_INVALIDNUMBER, asError[*strconv.NumError]),
+	standardTranslator("*json.SyntaxError", E_INVALIDJSON, asError[*json.SyntaxError]),
+	standardTranslator("*exec.ExitError", E_EXITSTATUS, asError[*exec.ExitError]),
+	standardTranslator("*net.OpError", E_NETWORK, asError[*net.OpError]),
+	standardTranslator("*fs.PathError", E_FILESYSTEM, asError[*fs.PathError]),
+	standardTranslator("syscall.Errno", E_SYSCALL, asError[syscall.Errno]),
+}
+
+func standardTranslator(name, identifier string, match func(err error) bool) Translator {
+	return Translator{
+		Name:  name,
+		Match: match,
+		Translate: func(err error) Translation {
+			return Translation{
+				Identifier: identifier,
+				Attributes: standardAttributes(err),
+			}
+		},
+	}
+}
+
+// RegisterTranslator registers a translator into the registry, consulted by its From and Translate methods
+func (r *Registry) RegisterTranslator(translator Translator) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	index := len(r.translators)
+	for i, registered := range r.translators {
+		if translator.Priority > registered.Priority {
+			index = i
+			break
+		}
+	}
+	r.translators = append(r.translators[:index], append([]Translator{translator}, r.translators[index:]...)...)
+}
+
+/*
+Translate converts an error through the first matching translator of the registry, or the standard library ones.
+Returns the converted error, and the name of the matching translator.
+*/
+func (r *Registry) Translate(err error) (eerr Eerror, rule string, ok bool) {
+	var original interface{} = err
+	return r.translate(&original)
+}
+
+func (r *Registry) translate(err *interface{}) (Eerror, string, bool) {
+	stdErr, ok := (*err).(error)
+	if !ok || stdErr == nil {
+		return Eerror{}, "", false
+	}
+
+	r.mutex.RLock()
+	translators := append(r.translators[:len(r.translators):len(r.translators)], standardTranslators...)
+	r.mutex.RUnlock()
+
+	for _, translator := range translators {
+		if !translator.Match(stdErr) {
+			continue
+		}
+
+		translation := translator.Translate(stdErr)
+		if translation.Message == "" {
+			translation.Message = stdErr.Error()
+		}
+		eerr := Eerror{
+			err,
+
+			translation.Identifier,
+			translation.Message,
+			[]string{},
+			nil,
+			nil,
+			0,
+			0,
+			"",
+			nil,
+			nil,
+			nil,
+			time.Now(),
+			generateUniqueID(),
+		}
+		eerr.WithAttributes(translation.Attributes...)
+		eerr.withInternalAttributes("stacktrace", string(debug.Stack()))
+		return eerr, translator.Name, true
+	}
+	return Eerror{}, "", false
+}
+
+// RegisterTranslator registers a translator into the default registry, consulted by From
+func RegisterTranslator(translator Translator) {
+	DefaultRegistry.RegisterTranslator(translator)
+}
+
+// Translate converts an error through the translators of the default registry, reporting the matching translator name
+func Translate(err error) (eerr Eerror, rule string, ok bool) {
+	return DefaultRegistry.Translate(err)
+}
+
+// standardAttributes describes the well-known errors wrapped by an error
+func standardAttributes(err error) []interface{} {
+	var attributes []interface{}
+
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		attributes = append(attributes, "op", pathErr.Op, "path", pathErr.Path)
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		attributes = append(attributes, "op", opErr.Op, "net", opErr.Net, "timeout", opErr.Timeout())
+		if opErr.Addr != nil {
+			attributes = append(attributes, "addr", opErr.Addr.String())
+		}
+	}
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		attributes = append(attributes, "errno", int(errno))
+	}
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		attributes = append(attributes, "func", numErr.Func, "input", numErr.Num)
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		attributes = append(attributes, "offset", syntaxErr.Offset)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		attributes = append(attributes, "exit_code", exitErr.ExitCode())
+	}
+	return attributes
+}
+
+func isError(target error) func(err error) bool {
+	return func(err error) bool {
+		return errors.Is(err, target)
+	}
+}
+
+func asError[T error](err error) bool {
+	var target T
+	return errors.As(err, &target)
+}
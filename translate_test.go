@@ -0,0 +1,119 @@
+package eerror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+	"testing"
+)
+
+// TestStandardTranslations ensures well-known standard library errors are given meaningful identifiers and attributes
+func TestStandardTranslations(t *testing.T) {
+	_, openErr := os.Open("/nonexistent/file")
+	_, numErr := strconv.Atoi("forty-two")
+	jsonErr := json.Unmarshal([]byte("{invalid"), &struct{}{})
+	_, dialErr := net.Dial("tcp", "127.0.0.1:1")
+	exitErr := exec.Command("sh", "-c", "exit 3").Run()
+
+	for _, test := range []struct {
+		err        error
+		identifier string
+		attributes map[string]interface{}
+	}{
+		{openErr, E_NOTEXIST, map[string]interface{}{"op": "open", "path": "/nonexistent/file", "errno": int(syscall.ENOENT)}},
+		{&os.PathError{Op: "chmod", Path: "/root", Err: syscall.EPERM}, E_PERMISSIONDENIED, map[string]interface{}{"path": "/root"}},
+		{&os.PathError{Op: "read", Path: "/dev/x", Err: syscall.EIO}, E_FILESYSTEM, map[string]interface{}{"op": "read"}},
+		{context.Canceled, E_CANCELED, nil},
+		{fmt.Errorf("rpc: %w", context.DeadlineExceeded), E_DEADLINEEXCEEDED, nil},
+		{io.EOF, E_EOF, nil},
+		{io.ErrUnexpectedEOF, E_UNEXPECTEDEOF, nil},
+		{numErr, E_INVALIDNUMBER, map[string]interface{}{"func": "Atoi", "input": "forty-two"}},
+		{jsonErr, E_INVALIDJSON, map[string]interface{}{"offset": int64(2)}},
+		{dialErr, E_NETWORK, map[string]interface{}{"op": "dial", "net": "tcp"}},
+		{syscall.EINVAL, E_SYSCALL, map[string]interface{}{"errno": int(syscall.EINVAL)}},
+		{exitErr, E_EXITSTATUS, map[string]interface{}{"exit_code": 3}},
+	} {
+		eerr := From(test.err)
+		if eerr.Id() != test.identifier {
+			t.Error("Invalid translated identifier (error, result, expected)\n", test.err, eerr.Id(), test.identifier)
+			continue
+		}
+		if !eerr.Is(test.err) || eerr.Message() != test.err.Error() {
+			t.Error("Translated error should be related to the original error\n", eerr)
+		}
+		for key, value := range test.attributes {
+			if eerr.GetAttributes()[key] != value {
+				t.Error("Invalid translated attribute (error, attribute, result, expected)\n", test.err, key, eerr.GetAttributes()[key], value)
+			}
+		}
+	}
+
+	if eerr := From(fmt.Errorf("unknown error")); eerr.Id() != E_EXTERNALERROR {
+		t.Error("Unknown errors should keep the generic identifier\n", eerr.Id())
+	}
+}
+
+type driverError struct {
+	code string
+}
+
+func (e *driverError) Error() string {
+	return "driver error " + e.code
+}
+
+// TestTranslators ensures registered translators are consulted by priority, before the standard ones
+func TestTranslators(t *testing.T) {
+	registry := NewRegistry()
+	registry.RegisterTranslator(TranslateType("driver error", 0, func(err *driverError) Translation {
+		return Translation{Identifier: E_TESTERROR, Attributes: []interface{}{"code", err.code}}
+	}))
+	registry.RegisterTranslator(Translator{
+		Name:     "unique violation",
+		Priority: 10,
+		Match: func(err error) bool {
+			var driverErr *driverError
+			return errors.As(err, &driverErr) && driverErr.code == "23505"
+		},
+		Translate: func(err error) Translation {
+			return Translation{Identifier: E_TESTERROR_WITH_ATTRIBUTES, Message: "duplicated entry"}
+		},
+	})
+	registry.RegisterTranslator(Translator{
+		Name:      "io.EOF override",
+		Match:     func(err error) bool { return err == io.EOF },
+		Translate: func(err error) Translation { return Translation{Identifier: E_TESTERROR} },
+	})
+
+	for _, test := range []struct {
+		err        error
+		identifier string
+		rule       string
+	}{
+		{&driverError{"42P01"}, E_TESTERROR, "driver error"},
+		{fmt.Errorf("insert: %w", &driverError{"23505"}), E_TESTERROR_WITH_ATTRIBUTES, "unique violation"},
+		{io.EOF, E_TESTERROR, "io.EOF override"},
+		{io.ErrUnexpectedEOF, E_UNEXPECTEDEOF, "io.ErrUnexpectedEOF"},
+	} {
+		eerr, rule, ok := registry.Translate(test.err)
+		if !ok || eerr.Id() != test.identifier || rule != test.rule {
+			t.Error("Invalid translation (error, identifier, expected, rule, expected)\n", test.err, eerr.Id(), test.identifier, rule, test.rule)
+		}
+		if from := registry.From(test.err); from.Id() != test.identifier {
+			t.Error("Registry From should consult its translators\n", from.Id(), test.identifier)
+		}
+	}
+
+	if eerr, _, _ := registry.Translate(&driverError{"42P01"}); eerr.GetAttributes()["code"] != "42P01" || eerr.Message() != "driver error 42P01" {
+		t.Error("Translation should set attributes, and default to the error message\n", eerr)
+	}
+	if _, _, ok := Translate(&driverError{"42P01"}); ok {
+		t.Error("Registry translators shouldn't be consulted by the default registry")
+	}
+}
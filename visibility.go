@@ -0,0 +1,181 @@
+package eerror
+
+// Visibility describes who may see an attribute
+type Visibility int
+
+const (
+	// VisibilityInternal attributes are only displayed to the application developers and operators (default)
+	VisibilityInternal Visibility = iota
+	// VisibilityPublic attributes are safe to be displayed to clients
+	VisibilityPublic
+	// VisibilitySecret attributes are never displayed, their value being redacted according to the secret policy
+	VisibilitySecret
+)
+
+// Audience describes who an error is rendered for
+type Audience int
+
+const (
+	// AudienceInternal renders the whole error, secret attributes excepted
+	AudienceInternal Audience = iota
+	// AudiencePublic renders the public message and attributes of the error only
+	AudiencePublic
+)
+
+// Redacted replaces the values of secret attributes
+const Redacted = "[REDACTED]"
+
+// DefaultPublicMessage is the public message of errors not declaring one
+var DefaultPublicMessage = "An unexpected error occurred"
+
+func (v Visibility) String() string {
+	switch v {
+	case VisibilityPublic:
+		return "public"
+	case VisibilitySecret:
+		return "secret"
+	}
+	return "internal"
+}
+
+// SetVisibility sets the default visibility of the given attributes, on every error
+func (r *Registry) SetVisibility(visibility Visibility, keys ...string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.visibility == nil {
+		r.visibility = make(map[string]Visibility)
+	}
+	for _, key := range keys {
+		r.visibility[key] = visibility
+	}
+}
+
+// Visibility returns the default visibility of an attribute, VisibilityInternal if not set
+func (r *Registry) Visibility(key string) Visibility {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.visibility[key]
+}
+
+// SetVisibility sets the default visibility of the given attributes, on every error, in the default registry
+func SetVisibility(visibility Visibility, keys ...string) {
+	DefaultRegistry.SetVisibility(visibility, keys...)
+}
+
+/*
+WithPublicMessage sets the message displayed to clients, rendered from public attributes only.
+The message of an error is considered internal: errors without public message are rendered to clients as DefaultPublicMessage.
+
+  eerr := eerror.NewError(E_USER_NOT_FOUND, "user {user_id} not found in shard {shard}", "user_id", 42, "shard", "eu-1")
+  eerr.WithPublicMessage("user {user_id} not found")
+  eerr.WithVisibility(eerror.VisibilityPublic, "user_id")
+*/
+func (e *Eerror) WithPublicMessage(message string) {
+	e.public = message
+}
+
+// WithVisibility sets the visibility of the given attributes, overriding the registry defaults
+func (e *Eerror) WithVisibility(visibility Visibility, keys ...string) {
+	e.visibilities = copyVisibilities(e.visibilities, len(keys))
+	for _, key := range keys {
+		e.visibilities[key] = visibility
+	}
+}
+
+// Visibility returns the visibility of an attribute, as set on the error or defaulted from the default registry
+func (e Eerror) Visibility(key string) Visibility {
+	if visibility, ok := e.visibilities[key]; ok {
+		return visibility
+	}
+	return DefaultRegistry.Visibility(key)
+}
+
+// PublicMessage renders the message displayed to clients, from public attributes only
+func (e Eerror) PublicMessage() string {
+	if e.public == "" {
+		return DefaultPublicMessage
+	}
+	return interpolate(e.public, e.visibleAttributes(AudiencePublic))
+}
+
+/*
+Render formats any error for the given audience, as a marshable object.
+The public audience gets the identifier, public message and public attributes of the error only:
+
+  {"code": "E_USER_NOT_FOUND", "message": "user 42 not found", "attributes": {"user_id": 42}}
+
+The internal audience gets the error as formatted by Map, along with its public message.
+*/
+func Render(err interface{}, audience Audience) map[string]interface{} {
+	return DefaultCatalog.Render(err, audience, "")
+}
+
+/*
+Render formats any error for the given audience as the package Render does, its message being localized in the given locale.
+Catalog messages are displayed to clients: they are considered public, and rendered from public attributes only for the public audience.
+*/
+func (c *Catalog) Render(err interface{}, audience Audience, locale string) map[string]interface{} {
+	eerr := From(err)
+
+	if audience == AudienceInternal {
+		rendering := eerr.Map()
+		rendering["public_message"] = eerr.PublicMessage()
+		if locale != "" {
+			rendering["message"] = c.Localize(eerr, locale)
+		}
+		return rendering
+	}
+
+	attributes := eerr.visibleAttributes(AudiencePublic)
+	message, ok := c.localize(eerr, locale, attributes)
+	if !ok {
+		message = eerr.PublicMessage()
+	}
+	return map[string]interface{}{
+		"code":       eerr.identifier,
+		"message":    message,
+		"attributes": attributes,
+	}
+}
+
+// visibleAttributes returns a copy of the attributes visible to the audience, lazy values resolved then redacted by the default registry rules
+func (e Eerror) visibleAttributes(audience Audience) map[string]interface{} {
+	visible := make(map[string]interface{}, len(e.attributes))
+	for key, value := range e.attributes {
+		visibility := e.Visibility(key)
+		if audience == AudiencePublic && visibility != VisibilityPublic {
+			continue
+		}
+		if value, ok := DefaultRegistry.redact(key, resolve(value), visibility == VisibilitySecret); ok {
+			visible[key] = value
+		}
+	}
+	return visible
+}
+
+// placeholderAttributes returns the attributes named by the placeholders of a template, as visibleAttributes does for internal audiences.
+// Other attributes are left unresolved
+func (e Eerror) placeholderAttributes(template string) map[string]interface{} {
+	visible := make(map[string]interface{})
+	for _, key := range placeholders(template) {
+		value, ok := e.attributes[key]
+		if _, done := visible[key]; !ok || done {
+			continue
+		}
+		if value, ok := DefaultRegistry.redact(key, resolve(value), e.Visibility(key) == VisibilitySecret); ok {
+			visible[key] = value
+		}
+	}
+	return visible
+}
+
+// copyVisibilities returns a new visibilities map holding the given ones, with room for extra visibilities
+func copyVisibilities(visibilities map[string]Visibility, extra int) map[string]Visibility {
+	copied := make(map[string]Visibility, len(visibilities)+extra)
+	for key, visibility := range visibilities {
+		copied[key] = visibility
+	}
+	return copied
+}
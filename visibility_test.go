@@ -0,0 +1,86 @@
+package eerror
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestRender ensures errors are rendered to the public audience without internal message nor attributes
+func TestRender(t *testing.T) {
+	eerr := NewError(E_TESTERROR, "user {user_id} not found in shard {shard} with {token}", "user_id", 42, "shard", "eu-1", "token", "t0k3n")
+	eerr.WithPublicMessage("user {user_id} not found in shard {shard}")
+	eerr.WithVisibility(VisibilityPublic, "user_id")
+	eerr.WithVisibility(VisibilitySecret, "token")
+
+	public := Render(eerr, AudiencePublic)
+	if public["code"] != E_TESTERROR || public["message"] != "user 42 not found in shard {shard}" {
+		t.Error("Public rendering should only interpolate public attributes\n", public)
+	}
+	if attributes := public["attributes"].(map[string]interface{}); len(attributes) != 1 || attributes["user_id"] != 42 {
+		t.Error("Public rendering should only hold public attributes\n", attributes)
+	}
+
+	internal := Render(eerr, AudienceInternal)
+	if internal["message"] != "user 42 not found in shard eu-1 with [REDACTED]" || internal["public_message"] != "user 42 not found in shard {shard}" {
+		t.Error("Internal rendering should redact secret attributes\n", internal)
+	}
+	if attributes := internal["attributes"].(map[string]interface{}); attributes["shard"] != "eu-1" || attributes["token"] != Redacted {
+		t.Error("Internal rendering should hold internal attributes, and redact secret ones\n", attributes)
+	}
+	if strings.Contains(eerr.Error(), "t0k3n") {
+		t.Error("Secret attributes shouldn't be formatted\n", eerr.Error())
+	}
+	if eerr.GetAttributes()["token"] != "t0k3n" {
+		t.Error("Secret attributes should remain accessible\n", eerr.GetAttributes())
+	}
+
+	if message := Render(NewError(E_TESTERROR, "internal failure"), AudiencePublic)["message"]; message != DefaultPublicMessage {
+		t.Error("Errors without public message should be rendered with the default one\n", message)
+	}
+}
+
+// TestTemplateVisibility ensures template instances inherit the public message and visibilities of their template
+func TestTemplateVisibility(t *testing.T) {
+	template := NewRegistry().Define(E_TESTERROR, "user {user_id} not found in shard {shard}").
+		Public("user {user_id} not found").
+		WithVisibility(VisibilityPublic, "user_id")
+
+	eerr := template.Instance("user_id", 42, "shard", "eu-1")
+	if eerr.PublicMessage() != "user 42 not found" || eerr.Visibility("shard") != VisibilityInternal {
+		t.Error("Instance should inherit its template public message and visibilities\n", eerr.PublicMessage())
+	}
+
+	eerr.WithVisibility(VisibilitySecret, "user_id")
+	if template.Instance("user_id", 42).Visibility("user_id") != VisibilityPublic {
+		t.Error("Instance visibilities shouldn't update its template ones\n")
+	}
+}
+
+// TestVisibilityZeroRegistry ensures attribute visibilities can be set on a zero-value registry
+func TestVisibilityZeroRegistry(t *testing.T) {
+	registry := &Registry{}
+	registry.SetVisibility(VisibilityPublic, "user_id")
+
+	if registry.Visibility("user_id") != VisibilityPublic {
+		t.Error("Zero-value registry should hold attribute visibilities\n", registry.Visibility("user_id"))
+	}
+}
+
+// TestPrint ensures command line tools print errors for the given audience
+func TestPrint(t *testing.T) {
+	eerr := NewError(E_TESTERROR, "user {user_id} not found in shard {shard}", "user_id", 42, "shard", "eu-1")
+	eerr.WithPublicMessage("user not found")
+
+	var output bytes.Buffer
+	Print(&output, eerr, AudiencePublic)
+	if output.String() != E_TESTERROR+": user not found\n" {
+		t.Error("Invalid public output\n", output.String())
+	}
+
+	output.Reset()
+	Print(&output, eerr, AudienceInternal)
+	if !strings.Contains(output.String(), "user 42 not found in shard eu-1") {
+		t.Error("Invalid internal output\n", output.String())
+	}
+}